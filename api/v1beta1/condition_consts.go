@@ -0,0 +1,74 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// InstanceReadyCondition reports whether the OpenNebula VM backing a ONEMachine is RUNNING.
+	InstanceReadyCondition clusterv1.ConditionType = "InstanceReady"
+
+	// InstanceProvisioningReason is used while the VM is PENDING, in PROLOG, BOOT, etc.
+	InstanceProvisioningReason = "InstanceProvisioning"
+	// InstanceNotRunningReason is used when a previously running VM left the RUNNING state.
+	InstanceNotRunningReason = "InstanceNotRunning"
+	// InstanceFailedReason is used when the VM reached a state it cannot recover from.
+	InstanceFailedReason = "InstanceFailed"
+	// InstanceProvisioningTimeoutReason is used when the VM did not reach RUNNING in time.
+	InstanceProvisioningTimeoutReason = "InstanceProvisioningTimeout"
+	// InstanceTerminatingReason is used after a graceful (ACPI) terminate has been requested.
+	InstanceTerminatingReason = "InstanceTerminating"
+	// InstanceTerminatingHardReason is used after falling back to a hard terminate.
+	InstanceTerminatingHardReason = "InstanceTerminatingHard"
+)
+
+const (
+	// LoadBalancerReadyCondition reports whether the control-plane endpoint has been allocated or verified.
+	LoadBalancerReadyCondition clusterv1.ConditionType = "LoadBalancerReady"
+
+	// LoadBalancerProvisioningFailedReason is used when the load balancer could not be created or synced.
+	LoadBalancerProvisioningFailedReason = "LoadBalancerProvisioningFailed"
+	// LoadBalancerEndpointInvalidReason is used when the control-plane endpoint does not match the load balancer.
+	LoadBalancerEndpointInvalidReason = "LoadBalancerEndpointInvalid"
+)
+
+const (
+	// ImagesReadyCondition reports whether all the images of the ONECluster have been imported.
+	ImagesReadyCondition clusterv1.ConditionType = "ImagesReady"
+
+	// ImagesImportingReason is used while images are being downloaded or imported.
+	ImagesImportingReason = "ImagesImporting"
+	// ImageImportFailedReason is used when an image reached the ERROR state.
+	ImageImportFailedReason = "ImageImportFailed"
+	// ImageImportTimeoutReason is used when an image was not imported in time.
+	ImageImportTimeoutReason = "ImageImportTimeout"
+)
+
+const (
+	// CloudAPIReadyCondition reports whether OpenNebula accepts the requests made for the object,
+	// it is only set after a request failed for a reason a quick retry will not fix.
+	CloudAPIReadyCondition clusterv1.ConditionType = "CloudAPIReady"
+
+	// CloudAuthFailedReason is used when OpenNebula rejected the credentials or their permissions.
+	CloudAuthFailedReason = "CloudAuthFailed"
+	// CloudQuotaExceededReason is used when a quota of the OpenNebula user or group is exhausted.
+	CloudQuotaExceededReason = "CloudQuotaExceeded"
+	// CloudConflictReason is used when an OpenNebula resource is locked or its name is already taken.
+	CloudConflictReason = "CloudConflict"
+)
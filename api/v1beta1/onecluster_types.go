@@ -28,12 +28,27 @@ const (
 )
 
 // ONEClusterSpec defines the desired state of ONECluster
+// +kubebuilder:validation:XValidation:rule="!has(self.loadBalancer) || self.loadBalancer.type != 'VirtualRouter' || has(self.virtualRouter)",message="loadBalancer type VirtualRouter requires virtualRouter"
+// +kubebuilder:validation:XValidation:rule="!has(self.loadBalancer) || self.loadBalancer.type == 'VirtualRouter' || !has(self.virtualRouter)",message="virtualRouter is only allowed with loadBalancer type VirtualRouter"
+// +kubebuilder:validation:XValidation:rule="!has(self.loadBalancer) || self.loadBalancer.type != 'External' || (has(self.controlPlaneEndpoint) && size(self.controlPlaneEndpoint.host) != 0)",message="loadBalancer type External requires controlPlaneEndpoint.host"
+// +kubebuilder:validation:XValidation:rule="(has(self.secretName) && size(self.secretName) != 0) || has(self.identityRef)",message="either secretName or identityRef must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.securityGroups) || has(self.publicNetwork) || has(self.privateNetwork)",message="securityGroups require publicNetwork or privateNetwork"
 type ONEClusterSpec struct {
 	// +optional
 	ControlPlaneEndpoint clusterv1.APIEndpoint `json:"controlPlaneEndpoint"`
 
-	// +required
-	SecretName string `json:"secretName"`
+	// Secret in the ONECluster namespace holding ONE_XMLRPC and ONE_AUTH.
+	// Optional keys: ONE_CA_BUNDLE, ONE_INSECURE_SKIP_VERIFY, ONE_HTTP_PROXY and ONE_TIMEOUT.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+
+	// Identity to take the credentials from instead of SecretName.
+	// +optional
+	IdentityRef *ONEClusterIdentityReference `json:"identityRef,omitempty"`
+
+	// Control-plane load balancer, defaults to VirtualRouter when virtualRouter is set and to External otherwise.
+	// +optional
+	LoadBalancer *ONELoadBalancer `json:"loadBalancer,omitempty"`
 
 	// +optional
 	VirtualRouter *ONEVirtualRouter `json:"virtualRouter,omitempty"`
@@ -49,6 +64,86 @@ type ONEClusterSpec struct {
 
 	// +optional
 	Templates []*ONETemplate `json:"templates,omitempty"`
+
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	FailureDomains []ONEFailureDomain `json:"failureDomains,omitempty"`
+
+	// +optional
+	VMGroup *ONEVMGroup `json:"vmGroup,omitempty"`
+
+	// +optional
+	VirtualRouterReservation *ONEReservation `json:"virtualRouterReservation,omitempty"`
+
+	// +optional
+	LoadBalancerReservation *ONEReservation `json:"loadBalancerReservation,omitempty"`
+
+	// +optional
+	SecurityGroups *ONESecurityGroups `json:"securityGroups,omitempty"`
+}
+
+type ONEClusterIdentityReference struct {
+	// +optional
+	// +kubebuilder:validation:Enum=ONEClusterIdentity
+	// +kubebuilder:default=ONEClusterIdentity
+	Kind string `json:"kind,omitempty"`
+
+	// +required
+	Name string `json:"name"`
+}
+
+// ONEFailureDomain maps a CAPI failure domain onto an OpenNebula cluster.
+type ONEFailureDomain struct {
+	// +required
+	Name string `json:"name"`
+
+	// +required
+	// +kubebuilder:validation:Minimum=0
+	ClusterID int32 `json:"clusterID"`
+
+	// +optional
+	// +kubebuilder:default=true
+	ControlPlane *bool `json:"controlPlane,omitempty"`
+
+	// Extra expression AND-ed into SCHED_REQUIREMENTS, e.g. `HOST_GROUP = "rack1"`.
+	// +optional
+	HostRequirements *string `json:"hostRequirements,omitempty"`
+
+	// Extra expression AND-ed into SCHED_DS_REQUIREMENTS.
+	// +optional
+	DatastoreRequirements *string `json:"datastoreRequirements,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=VirtualRouter;KubeVIP;External
+type ONELoadBalancerType string
+
+const (
+	// LoadBalancerTypeVirtualRouter balances the control plane with HAProxy running in an OpenNebula VR.
+	LoadBalancerTypeVirtualRouter ONELoadBalancerType = "VirtualRouter"
+	// LoadBalancerTypeKubeVIP floats the control-plane endpoint between control-plane machines with kube-vip.
+	LoadBalancerTypeKubeVIP ONELoadBalancerType = "KubeVIP"
+	// LoadBalancerTypeExternal uses a load balancer managed outside of the provider.
+	LoadBalancerTypeExternal ONELoadBalancerType = "External"
+)
+
+// +kubebuilder:validation:XValidation:rule="self.type == 'KubeVIP' || !has(self.kubeVIP)",message="kubeVIP is only allowed with type KubeVIP"
+type ONELoadBalancer struct {
+	// +optional
+	// +kubebuilder:default=VirtualRouter
+	Type ONELoadBalancerType `json:"type,omitempty"`
+
+	// +optional
+	KubeVIP *ONEKubeVIP `json:"kubeVIP,omitempty"`
+}
+
+type ONEKubeVIP struct {
+	// Network the control-plane VIP is held in. A free address is picked
+	// when controlPlaneEndpoint.host is empty. A host outside the network
+	// address ranges is treated as externally managed and not held.
+	// +optional
+	// +kubebuilder:default=public
+	Network ONENetworkRole `json:"network,omitempty"`
 }
 
 type ONEVirtualRouter struct {
@@ -58,13 +153,38 @@ type ONEVirtualRouter struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Deprecated: use Listeners instead. Each port is used as both the frontend and the backend port.
 	// +optional
 	ListenerPorts []int32 `json:"listenerPorts,omitempty"`
 
+	// Listeners configured on the VR load balancer, takes precedence over ListenerPorts.
+	// +optional
+	// +listType=map
+	// +listMapKey=port
+	Listeners []ONEVirtualRouterListener `json:"listeners,omitempty"`
+
 	// +optional
 	ExtraContext map[string]string `json:"extraContext,omitempty"`
 }
 
+// ONEVirtualRouterListener is a TCP frontend of the VR HAProxy load balancer.
+// The VNF appliance renders every frontend in TCP mode and checks backends with
+// fixed settings, it reads no ONEAPP_VNF_HAPROXY_LBn_* or ONEGATE_HAPROXY_* keys
+// for the protocol or the check interval/fall/rise, so neither is configurable.
+type ONEVirtualRouterListener struct {
+	// Frontend port exposed on the VR.
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// Backend port on the control-plane machines, defaults to Port.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	TargetPort *int32 `json:"targetPort,omitempty"`
+}
+
 type ONEVirtualNetwork struct {
 	// +required
 	Name string `json:"name"`
@@ -72,24 +192,132 @@ type ONEVirtualNetwork struct {
 	// +optional
 	FloatingIP *string `json:"floatingIP,omitempty"`
 
+	// IPv6 floating IP, only used with IPv6 or dual-stack networks.
+	// +optional
+	FloatingIP6 *string `json:"floatingIP6,omitempty"`
+
 	// +optional
 	FloatingOnly *bool `json:"floatingOnly,omitempty"`
 
 	// +optional
 	Gateway *string `json:"gateway,omitempty"`
 
+	// +optional
+	Gateway6 *string `json:"gateway6,omitempty"`
+
 	// +optional
 	DNS *string `json:"dns,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=ANTI_AFFINED;AFFINED;NONE
+type ONEVMGroupPolicy string
+
+const (
+	VMGroupPolicyAntiAffined ONEVMGroupPolicy = "ANTI_AFFINED"
+	VMGroupPolicyAffined     ONEVMGroupPolicy = "AFFINED"
+	VMGroupPolicyNone        ONEVMGroupPolicy = "NONE"
+)
+
+// ONEVMGroup configures the per-cluster OpenNebula VM group, which places
+// control-plane and worker VMs into separate roles. Both roles spread their
+// VMs across hosts by default. Anti-affinity is enforced by the scheduler,
+// so a role cannot run more VMs than there are hosts. Use NONE to lift it.
+type ONEVMGroup struct {
+	// +optional
+	// +kubebuilder:default=ANTI_AFFINED
+	ControlPlanePolicy ONEVMGroupPolicy `json:"controlPlanePolicy,omitempty"`
+
+	// +optional
+	// +kubebuilder:default=ANTI_AFFINED
+	WorkerPolicy ONEVMGroupPolicy `json:"workerPolicy,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=public;private
+type ONENetworkRole string
+
+const (
+	NetworkRolePublic  ONENetworkRole = "public"
+	NetworkRolePrivate ONENetworkRole = "private"
+)
+
+// ONEReservation requests an address reservation carved out of the
+// cluster's public or private network.
+type ONEReservation struct {
+	// +required
+	Network ONENetworkRole `json:"network"`
+
+	// +required
+	// +kubebuilder:validation:Minimum=1
+	Size int32 `json:"size"`
+}
+
+// ONESecurityGroups configures the security groups managed for control-plane
+// and worker nodes. Ranges use the OpenNebula syntax, e.g. "2379:2380" or "22,80".
+// The etcd, kubelet and CNI rules only accept traffic from the node network (the
+// private network, or the public one when there is no private network), while the
+// API server, NodePort and extra rules accept traffic from any source.
+// NOTE: OpenNebula also applies the security groups of the virtual network to every
+// NIC, including the default security group 0 which allows all traffic. Remove it
+// from the cluster networks, otherwise these rules do not restrict anything.
+type ONESecurityGroups struct {
+	// +optional
+	// +kubebuilder:default=6443
+	APIServerPort int32 `json:"apiServerPort,omitempty"`
+
+	// +optional
+	// +kubebuilder:default=10250
+	KubeletPort int32 `json:"kubeletPort,omitempty"`
+
+	// +optional
+	// +kubebuilder:default="2379:2380"
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?(,[0-9]+(:[0-9]+)?)*$`
+	EtcdPortRange string `json:"etcdPortRange,omitempty"`
+
+	// +optional
+	// +kubebuilder:default="30000:32767"
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?(,[0-9]+(:[0-9]+)?)*$`
+	NodePortRange string `json:"nodePortRange,omitempty"`
+
+	// Defaults to Flannel VXLAN.
+	// +optional
+	// +kubebuilder:default={{protocol: UDP, range: "8472"}}
+	CNIRules []ONESecurityGroupRule `json:"cniRules,omitempty"`
+
+	// +optional
+	ExtraRules []ONESecurityGroupRule `json:"extraRules,omitempty"`
+}
+
+// +kubebuilder:validation:Enum=TCP;UDP
+type ONESecurityGroupProtocol string
+
+// ONESecurityGroupRule allows inbound traffic on the given ports.
+type ONESecurityGroupRule struct {
+	// +required
+	Protocol ONESecurityGroupProtocol `json:"protocol"`
+
+	// +required
+	// +kubebuilder:validation:Pattern=`^[0-9]+(:[0-9]+)?(,[0-9]+(:[0-9]+)?)*$`
+	Range string `json:"range"`
+}
+
 type ONETemplate struct {
 	// +required
 	TemplateName string `json:"templateName"`
 
 	// +required
 	TemplateContent string `json:"templateContent,omitempty"`
+
+	// Shared templates keep templateName as is and are reference-counted by the
+	// clusters using them, otherwise the template name is suffixed with the cluster UID.
+	// A template that already exists under the shared name and was not created by
+	// the provider is used as is, it is never reference-counted nor deleted.
+	// +optional
+	Shared bool `json:"shared,omitempty"`
 }
 
+// ONEImage is an image imported into OpenNebula. Each content is imported as a
+// separate image version: the first one is named imageName, later ones are
+// suffixed with their content hash, see status.images[].resolvedName.
 type ONEImage struct {
 	// +required
 	ImageName string `json:"imageName,omitempty"`
@@ -99,8 +327,27 @@ type ONEImage struct {
 
 	// +optional
 	ImageDatastoreId *uint `json:"imageDatastoreId,omitempty"`
+
+	// Give up when the image is not imported within this duration.
+	// +optional
+	ImportTimeout *metav1.Duration `json:"importTimeout,omitempty"`
+
+	// Whether the image (and its previous versions) is deleted along with the ONECluster.
+	// Images are shared by name, so they are only deleted once no other cluster uses them,
+	// images the provider neither created nor adopted are never deleted.
+	// +optional
+	// +kubebuilder:default=Retain
+	ReclaimPolicy ONEImageReclaimPolicy `json:"reclaimPolicy,omitempty"`
 }
 
+// +kubebuilder:validation:Enum=Retain;Delete
+type ONEImageReclaimPolicy string
+
+const (
+	ImageReclaimPolicyRetain ONEImageReclaimPolicy = "Retain"
+	ImageReclaimPolicyDelete ONEImageReclaimPolicy = "Delete"
+)
+
 // ONEClusterStatus defines the observed state of ONECluster
 type ONEClusterStatus struct {
 	// +optional
@@ -109,10 +356,158 @@ type ONEClusterStatus struct {
 	// +optional
 	FailureDomains clusterv1.FailureDomains `json:"failureDomains,omitempty"`
 
+	// +optional
+	VirtualRouterReservation *ONEReservationStatus `json:"virtualRouterReservation,omitempty"`
+
+	// +optional
+	LoadBalancerReservation *ONEReservationStatus `json:"loadBalancerReservation,omitempty"`
+
+	// +optional
+	SecurityGroups *ONESecurityGroupsStatus `json:"securityGroups,omitempty"`
+
+	// +optional
+	LoadBalancer *ONELoadBalancerStatus `json:"loadBalancer,omitempty"`
+
+	// +optional
+	Network *ONENetworkStatus `json:"network,omitempty"`
+
+	// +optional
+	// +listType=map
+	// +listMapKey=imageName
+	Images []ONEImageStatus `json:"images,omitempty"`
+
+	// VM templates created for the cluster, machines and the VR resolve template names through it.
+	// +optional
+	// +listType=map
+	// +listMapKey=templateName
+	Templates []ONETemplateStatus `json:"templates,omitempty"`
+
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+type ONEImageStatus struct {
+	// +required
+	ImageName string `json:"imageName"`
+
+	// Name of the image version holding imageContent in OpenNebula, it differs
+	// from imageName once the content has changed. Machine disks referencing
+	// imageName are pointed to it when their VMs are created.
+	// +optional
+	ResolvedName string `json:"resolvedName,omitempty"`
+
+	// +optional
+	ID int32 `json:"id"`
+
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Error reported by OpenNebula, or the reason the import was given up.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type ONETemplateStatus struct {
+	// +required
+	TemplateName string `json:"templateName"`
+
+	// Name of the VM template in OpenNebula.
+	// +required
+	ResolvedName string `json:"resolvedName"`
+
+	// +optional
+	Shared bool `json:"shared,omitempty"`
+}
+
+// ONENetworkStatus records the infrastructure discovered or allocated for the
+// cluster, machines are wired from it so the spec is never mutated.
+type ONENetworkStatus struct {
+	// +optional
+	VirtualRouterID *int32 `json:"virtualRouterID,omitempty"`
+
+	// +optional
+	VirtualRouterVMIDs []int32 `json:"virtualRouterVMIDs,omitempty"`
+
+	// +optional
+	Public *ONEVirtualNetworkStatus `json:"public,omitempty"`
+
+	// +optional
+	Private *ONEVirtualNetworkStatus `json:"private,omitempty"`
+}
+
+type ONEVirtualNetworkStatus struct {
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	ID *int32 `json:"id,omitempty"`
+
+	// +optional
+	FloatingIP string `json:"floatingIP,omitempty"`
+
+	// +optional
+	FloatingIP6 string `json:"floatingIP6,omitempty"`
+
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// +optional
+	Gateway6 string `json:"gateway6,omitempty"`
+
+	// +optional
+	DNS string `json:"dns,omitempty"`
+}
+
+type ONELoadBalancerStatus struct {
+	// +optional
+	Type ONELoadBalancerType `json:"type,omitempty"`
+
+	// Control-plane endpoint allocated or verified by the controller.
+	// +optional
+	Endpoint clusterv1.APIEndpoint `json:"endpoint,omitempty"`
+
+	// +optional
+	VirtualRouterID *int32 `json:"virtualRouterID,omitempty"`
+
+	// +optional
+	FloatingIPs []string `json:"floatingIPs,omitempty"`
+
+	// Network the KubeVIP endpoint address is held in. Unset when the address
+	// lies outside the network address ranges and is managed externally.
+	// +optional
+	LeaseNetworkID *int32 `json:"leaseNetworkID,omitempty"`
+}
+
+type ONESecurityGroupsStatus struct {
+	// +optional
+	ControlPlaneID int32 `json:"controlPlaneID"`
+
+	// +optional
+	WorkerID int32 `json:"workerID"`
+}
+
+type ONEReservationStatus struct {
+	// +optional
+	ID int32 `json:"id"`
+
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// +optional
+	ParentNetworkID int32 `json:"parentNetworkID"`
+
+	// +optional
+	Ranges []ONEAddressRange `json:"ranges,omitempty"`
+}
+
+type ONEAddressRange struct {
+	// +optional
+	IP string `json:"ip,omitempty"`
+
+	// +optional
+	Size int32 `json:"size"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
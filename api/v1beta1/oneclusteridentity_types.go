@@ -0,0 +1,69 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
+
+// ONEClusterIdentitySpec defines the desired state of ONEClusterIdentity
+type ONEClusterIdentitySpec struct {
+	// Name of the Secret holding ONE_XMLRPC and ONE_AUTH, it must live in the
+	// namespace the controller is running in. It accepts the same optional keys
+	// as ONECluster secrets (ONE_CA_BUNDLE, ONE_HTTP_PROXY, ...).
+	// +required
+	SecretName string `json:"secretName"`
+
+	// AllowedNamespaces selects the namespaces ONEClusters may use this identity from.
+	// An empty object allows all namespaces, if it is omitted no namespace is allowed.
+	// +optional
+	AllowedNamespaces *ONEAllowedNamespaces `json:"allowedNamespaces,omitempty"`
+}
+
+type ONEAllowedNamespaces struct {
+	// +optional
+	NamespaceList []string `json:"list,omitempty"`
+
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// ONEClusterIdentity is the Schema for the oneclusteridentities API
+type ONEClusterIdentity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ONEClusterIdentitySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ONEClusterIdentityList contains a list of ONEClusterIdentity
+type ONEClusterIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ONEClusterIdentity `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ONEClusterIdentity{}, &ONEClusterIdentityList{})
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -34,6 +36,88 @@ type ONEMachineSpec struct {
 
 	// +required
 	TemplateName string `json:"templateName"`
+
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	VCPU *int32 `json:"vcpu,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MemoryMB *int32 `json:"memoryMB,omitempty"`
+
+	// Resizes the first DISK of the VM template, which must define one.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	RootDiskSizeMB *int32 `json:"rootDiskSizeMB,omitempty"`
+
+	// +optional
+	Disks []ONEMachineDisk `json:"disks,omitempty"`
+
+	// +optional
+	Networks []ONEMachineNetwork `json:"networks,omitempty"`
+
+	// Overrides the controller-wide provisioning timeout for this machine.
+	// +optional
+	ProvisioningTimeout *metav1.Duration `json:"provisioningTimeout,omitempty"`
+
+	// Overrides the controller-wide grace period between a graceful (ACPI)
+	// terminate and a hard terminate of the VM.
+	// +optional
+	TerminationGracePeriod *metav1.Duration `json:"terminationGracePeriod,omitempty"`
+}
+
+// ONEMachineDisk describes an extra disk attached to the machine, either
+// backed by an existing image or created as a volatile disk.
+// +kubebuilder:validation:XValidation:rule="has(self.imageName) || has(self.sizeMB)",message="either imageName or sizeMB must be set"
+type ONEMachineDisk struct {
+	// +optional
+	ImageName *string `json:"imageName,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	SizeMB *int32 `json:"sizeMB,omitempty"`
+
+	// +optional
+	Format *string `json:"format,omitempty"`
+
+	// +optional
+	Target *string `json:"target,omitempty"`
+
+	// +optional
+	DevPrefix *string `json:"devPrefix,omitempty"`
+}
+
+// ONEMachineNetwork describes a NIC attached to the machine, NICs are
+// created in the same order as they are listed.
+// +kubebuilder:validation:XValidation:rule="has(self.name) || has(self.id)",message="either name or id must be set"
+type ONEMachineNetwork struct {
+	// +optional
+	Name *string `json:"name,omitempty"`
+
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	ID *int32 `json:"id,omitempty"`
+
+	// +optional
+	IP *string `json:"ip,omitempty"`
+
+	// +optional
+	IP6 *string `json:"ip6,omitempty"`
+
+	// +optional
+	Gateway *string `json:"gateway,omitempty"`
+
+	// +optional
+	Gateway6 *string `json:"gateway6,omitempty"`
+
+	// +optional
+	DNS *string `json:"dns,omitempty"`
+
+	// +optional
+	SecurityGroups []int32 `json:"securityGroups,omitempty"`
 }
 
 // ONEMachineStatus defines the observed state of ONEMachine
@@ -44,10 +128,34 @@ type ONEMachineStatus struct {
 	// +optional
 	Addresses []clusterv1.MachineAddress `json:"addresses,omitempty"`
 
+	// +optional
+	InstanceState *ONEMachineInstanceState `json:"instanceState,omitempty"`
+
+	// FailureReason will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a succinct value suitable
+	// for machine interpretation.
+	// +optional
+	FailureReason *capierrors.MachineStatusError `json:"failureReason,omitempty"`
+
+	// FailureMessage will be set in the event that there is a terminal problem
+	// reconciling the Machine and will contain a more verbose string suitable
+	// for logging and human consumption.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// ONEMachineInstanceState mirrors the STATE and LCM_STATE of the OpenNebula VM.
+type ONEMachineInstanceState struct {
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// +optional
+	LCMState string `json:"lcmState,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 
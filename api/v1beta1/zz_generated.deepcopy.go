@@ -21,10 +21,52 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/errors"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEAddressRange) DeepCopyInto(out *ONEAddressRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEAddressRange.
+func (in *ONEAddressRange) DeepCopy() *ONEAddressRange {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEAddressRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEAllowedNamespaces) DeepCopyInto(out *ONEAllowedNamespaces) {
+	*out = *in
+	if in.NamespaceList != nil {
+		in, out := &in.NamespaceList, &out.NamespaceList
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEAllowedNamespaces.
+func (in *ONEAllowedNamespaces) DeepCopy() *ONEAllowedNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEAllowedNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONECluster) DeepCopyInto(out *ONECluster) {
 	*out = *in
@@ -52,6 +94,99 @@ func (in *ONECluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEClusterIdentity) DeepCopyInto(out *ONEClusterIdentity) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEClusterIdentity.
+func (in *ONEClusterIdentity) DeepCopy() *ONEClusterIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEClusterIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ONEClusterIdentity) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEClusterIdentityList) DeepCopyInto(out *ONEClusterIdentityList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ONEClusterIdentity, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEClusterIdentityList.
+func (in *ONEClusterIdentityList) DeepCopy() *ONEClusterIdentityList {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEClusterIdentityList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ONEClusterIdentityList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEClusterIdentityReference) DeepCopyInto(out *ONEClusterIdentityReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEClusterIdentityReference.
+func (in *ONEClusterIdentityReference) DeepCopy() *ONEClusterIdentityReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEClusterIdentityReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEClusterIdentitySpec) DeepCopyInto(out *ONEClusterIdentitySpec) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = new(ONEAllowedNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEClusterIdentitySpec.
+func (in *ONEClusterIdentitySpec) DeepCopy() *ONEClusterIdentitySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEClusterIdentitySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEClusterList) DeepCopyInto(out *ONEClusterList) {
 	*out = *in
@@ -88,6 +223,16 @@ func (in *ONEClusterList) DeepCopyObject() runtime.Object {
 func (in *ONEClusterSpec) DeepCopyInto(out *ONEClusterSpec) {
 	*out = *in
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
+	if in.IdentityRef != nil {
+		in, out := &in.IdentityRef, &out.IdentityRef
+		*out = new(ONEClusterIdentityReference)
+		**out = **in
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(ONELoadBalancer)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.VirtualRouter != nil {
 		in, out := &in.VirtualRouter, &out.VirtualRouter
 		*out = new(ONEVirtualRouter)
@@ -110,7 +255,7 @@ func (in *ONEClusterSpec) DeepCopyInto(out *ONEClusterSpec) {
 			if (*in)[i] != nil {
 				in, out := &(*in)[i], &(*out)[i]
 				*out = new(ONEImage)
-				**out = **in
+				(*in).DeepCopyInto(*out)
 			}
 		}
 	}
@@ -125,6 +270,33 @@ func (in *ONEClusterSpec) DeepCopyInto(out *ONEClusterSpec) {
 			}
 		}
 	}
+	if in.FailureDomains != nil {
+		in, out := &in.FailureDomains, &out.FailureDomains
+		*out = make([]ONEFailureDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.VMGroup != nil {
+		in, out := &in.VMGroup, &out.VMGroup
+		*out = new(ONEVMGroup)
+		**out = **in
+	}
+	if in.VirtualRouterReservation != nil {
+		in, out := &in.VirtualRouterReservation, &out.VirtualRouterReservation
+		*out = new(ONEReservation)
+		**out = **in
+	}
+	if in.LoadBalancerReservation != nil {
+		in, out := &in.LoadBalancerReservation, &out.LoadBalancerReservation
+		*out = new(ONEReservation)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = new(ONESecurityGroups)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEClusterSpec.
@@ -147,6 +319,41 @@ func (in *ONEClusterStatus) DeepCopyInto(out *ONEClusterStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.VirtualRouterReservation != nil {
+		in, out := &in.VirtualRouterReservation, &out.VirtualRouterReservation
+		*out = new(ONEReservationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LoadBalancerReservation != nil {
+		in, out := &in.LoadBalancerReservation, &out.LoadBalancerReservation
+		*out = new(ONEReservationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = new(ONESecurityGroupsStatus)
+		**out = **in
+	}
+	if in.LoadBalancer != nil {
+		in, out := &in.LoadBalancer, &out.LoadBalancer
+		*out = new(ONELoadBalancerStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Network != nil {
+		in, out := &in.Network, &out.Network
+		*out = new(ONENetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]ONEImageStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Templates != nil {
+		in, out := &in.Templates, &out.Templates
+		*out = make([]ONETemplateStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -166,9 +373,49 @@ func (in *ONEClusterStatus) DeepCopy() *ONEClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEFailureDomain) DeepCopyInto(out *ONEFailureDomain) {
+	*out = *in
+	if in.ControlPlane != nil {
+		in, out := &in.ControlPlane, &out.ControlPlane
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HostRequirements != nil {
+		in, out := &in.HostRequirements, &out.HostRequirements
+		*out = new(string)
+		**out = **in
+	}
+	if in.DatastoreRequirements != nil {
+		in, out := &in.DatastoreRequirements, &out.DatastoreRequirements
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEFailureDomain.
+func (in *ONEFailureDomain) DeepCopy() *ONEFailureDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEFailureDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEImage) DeepCopyInto(out *ONEImage) {
 	*out = *in
+	if in.ImageDatastoreId != nil {
+		in, out := &in.ImageDatastoreId, &out.ImageDatastoreId
+		*out = new(uint)
+		**out = **in
+	}
+	if in.ImportTimeout != nil {
+		in, out := &in.ImportTimeout, &out.ImportTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEImage.
@@ -181,6 +428,87 @@ func (in *ONEImage) DeepCopy() *ONEImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEImageStatus) DeepCopyInto(out *ONEImageStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEImageStatus.
+func (in *ONEImageStatus) DeepCopy() *ONEImageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEImageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEKubeVIP) DeepCopyInto(out *ONEKubeVIP) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEKubeVIP.
+func (in *ONEKubeVIP) DeepCopy() *ONEKubeVIP {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEKubeVIP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONELoadBalancer) DeepCopyInto(out *ONELoadBalancer) {
+	*out = *in
+	if in.KubeVIP != nil {
+		in, out := &in.KubeVIP, &out.KubeVIP
+		*out = new(ONEKubeVIP)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONELoadBalancer.
+func (in *ONELoadBalancer) DeepCopy() *ONELoadBalancer {
+	if in == nil {
+		return nil
+	}
+	out := new(ONELoadBalancer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONELoadBalancerStatus) DeepCopyInto(out *ONELoadBalancerStatus) {
+	*out = *in
+	out.Endpoint = in.Endpoint
+	if in.VirtualRouterID != nil {
+		in, out := &in.VirtualRouterID, &out.VirtualRouterID
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FloatingIPs != nil {
+		in, out := &in.FloatingIPs, &out.FloatingIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.LeaseNetworkID != nil {
+		in, out := &in.LeaseNetworkID, &out.LeaseNetworkID
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONELoadBalancerStatus.
+func (in *ONELoadBalancerStatus) DeepCopy() *ONELoadBalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONELoadBalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEMachine) DeepCopyInto(out *ONEMachine) {
 	*out = *in
@@ -208,6 +536,61 @@ func (in *ONEMachine) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEMachineDisk) DeepCopyInto(out *ONEMachineDisk) {
+	*out = *in
+	if in.ImageName != nil {
+		in, out := &in.ImageName, &out.ImageName
+		*out = new(string)
+		**out = **in
+	}
+	if in.SizeMB != nil {
+		in, out := &in.SizeMB, &out.SizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Format != nil {
+		in, out := &in.Format, &out.Format
+		*out = new(string)
+		**out = **in
+	}
+	if in.Target != nil {
+		in, out := &in.Target, &out.Target
+		*out = new(string)
+		**out = **in
+	}
+	if in.DevPrefix != nil {
+		in, out := &in.DevPrefix, &out.DevPrefix
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEMachineDisk.
+func (in *ONEMachineDisk) DeepCopy() *ONEMachineDisk {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEMachineDisk)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEMachineInstanceState) DeepCopyInto(out *ONEMachineInstanceState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEMachineInstanceState.
+func (in *ONEMachineInstanceState) DeepCopy() *ONEMachineInstanceState {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEMachineInstanceState)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEMachineList) DeepCopyInto(out *ONEMachineList) {
 	*out = *in
@@ -240,6 +623,61 @@ func (in *ONEMachineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEMachineNetwork) DeepCopyInto(out *ONEMachineNetwork) {
+	*out = *in
+	if in.Name != nil {
+		in, out := &in.Name, &out.Name
+		*out = new(string)
+		**out = **in
+	}
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = new(string)
+		**out = **in
+	}
+	if in.IP6 != nil {
+		in, out := &in.IP6, &out.IP6
+		*out = new(string)
+		**out = **in
+	}
+	if in.Gateway != nil {
+		in, out := &in.Gateway, &out.Gateway
+		*out = new(string)
+		**out = **in
+	}
+	if in.Gateway6 != nil {
+		in, out := &in.Gateway6, &out.Gateway6
+		*out = new(string)
+		**out = **in
+	}
+	if in.DNS != nil {
+		in, out := &in.DNS, &out.DNS
+		*out = new(string)
+		**out = **in
+	}
+	if in.SecurityGroups != nil {
+		in, out := &in.SecurityGroups, &out.SecurityGroups
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEMachineNetwork.
+func (in *ONEMachineNetwork) DeepCopy() *ONEMachineNetwork {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEMachineNetwork)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEMachineSpec) DeepCopyInto(out *ONEMachineSpec) {
 	*out = *in
@@ -248,6 +686,50 @@ func (in *ONEMachineSpec) DeepCopyInto(out *ONEMachineSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.VCPU != nil {
+		in, out := &in.VCPU, &out.VCPU
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MemoryMB != nil {
+		in, out := &in.MemoryMB, &out.MemoryMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RootDiskSizeMB != nil {
+		in, out := &in.RootDiskSizeMB, &out.RootDiskSizeMB
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Disks != nil {
+		in, out := &in.Disks, &out.Disks
+		*out = make([]ONEMachineDisk, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Networks != nil {
+		in, out := &in.Networks, &out.Networks
+		*out = make([]ONEMachineNetwork, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProvisioningTimeout != nil {
+		in, out := &in.ProvisioningTimeout, &out.ProvisioningTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TerminationGracePeriod != nil {
+		in, out := &in.TerminationGracePeriod, &out.TerminationGracePeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEMachineSpec.
@@ -268,6 +750,21 @@ func (in *ONEMachineStatus) DeepCopyInto(out *ONEMachineStatus) {
 		*out = make([]apiv1beta1.MachineAddress, len(*in))
 		copy(*out, *in)
 	}
+	if in.InstanceState != nil {
+		in, out := &in.InstanceState, &out.InstanceState
+		*out = new(ONEMachineInstanceState)
+		**out = **in
+	}
+	if in.FailureReason != nil {
+		in, out := &in.FailureReason, &out.FailureReason
+		*out = new(errors.MachineStatusError)
+		**out = **in
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -394,6 +891,131 @@ func (in *ONEMachineTemplateStatus) DeepCopy() *ONEMachineTemplateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONENetworkStatus) DeepCopyInto(out *ONENetworkStatus) {
+	*out = *in
+	if in.VirtualRouterID != nil {
+		in, out := &in.VirtualRouterID, &out.VirtualRouterID
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VirtualRouterVMIDs != nil {
+		in, out := &in.VirtualRouterVMIDs, &out.VirtualRouterVMIDs
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	if in.Public != nil {
+		in, out := &in.Public, &out.Public
+		*out = new(ONEVirtualNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Private != nil {
+		in, out := &in.Private, &out.Private
+		*out = new(ONEVirtualNetworkStatus)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONENetworkStatus.
+func (in *ONENetworkStatus) DeepCopy() *ONENetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONENetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEReservation) DeepCopyInto(out *ONEReservation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEReservation.
+func (in *ONEReservation) DeepCopy() *ONEReservation {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEReservation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEReservationStatus) DeepCopyInto(out *ONEReservationStatus) {
+	*out = *in
+	if in.Ranges != nil {
+		in, out := &in.Ranges, &out.Ranges
+		*out = make([]ONEAddressRange, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEReservationStatus.
+func (in *ONEReservationStatus) DeepCopy() *ONEReservationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEReservationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONESecurityGroupRule) DeepCopyInto(out *ONESecurityGroupRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONESecurityGroupRule.
+func (in *ONESecurityGroupRule) DeepCopy() *ONESecurityGroupRule {
+	if in == nil {
+		return nil
+	}
+	out := new(ONESecurityGroupRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONESecurityGroups) DeepCopyInto(out *ONESecurityGroups) {
+	*out = *in
+	if in.CNIRules != nil {
+		in, out := &in.CNIRules, &out.CNIRules
+		*out = make([]ONESecurityGroupRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraRules != nil {
+		in, out := &in.ExtraRules, &out.ExtraRules
+		*out = make([]ONESecurityGroupRule, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONESecurityGroups.
+func (in *ONESecurityGroups) DeepCopy() *ONESecurityGroups {
+	if in == nil {
+		return nil
+	}
+	out := new(ONESecurityGroups)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONESecurityGroupsStatus) DeepCopyInto(out *ONESecurityGroupsStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONESecurityGroupsStatus.
+func (in *ONESecurityGroupsStatus) DeepCopy() *ONESecurityGroupsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONESecurityGroupsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONETemplate) DeepCopyInto(out *ONETemplate) {
 	*out = *in
@@ -409,6 +1031,36 @@ func (in *ONETemplate) DeepCopy() *ONETemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONETemplateStatus) DeepCopyInto(out *ONETemplateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONETemplateStatus.
+func (in *ONETemplateStatus) DeepCopy() *ONETemplateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONETemplateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEVMGroup) DeepCopyInto(out *ONEVMGroup) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEVMGroup.
+func (in *ONEVMGroup) DeepCopy() *ONEVMGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEVMGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEVirtualNetwork) DeepCopyInto(out *ONEVirtualNetwork) {
 	*out = *in
@@ -417,6 +1069,11 @@ func (in *ONEVirtualNetwork) DeepCopyInto(out *ONEVirtualNetwork) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.FloatingIP6 != nil {
+		in, out := &in.FloatingIP6, &out.FloatingIP6
+		*out = new(string)
+		**out = **in
+	}
 	if in.FloatingOnly != nil {
 		in, out := &in.FloatingOnly, &out.FloatingOnly
 		*out = new(bool)
@@ -427,6 +1084,11 @@ func (in *ONEVirtualNetwork) DeepCopyInto(out *ONEVirtualNetwork) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Gateway6 != nil {
+		in, out := &in.Gateway6, &out.Gateway6
+		*out = new(string)
+		**out = **in
+	}
 	if in.DNS != nil {
 		in, out := &in.DNS, &out.DNS
 		*out = new(string)
@@ -444,6 +1106,26 @@ func (in *ONEVirtualNetwork) DeepCopy() *ONEVirtualNetwork {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEVirtualNetworkStatus) DeepCopyInto(out *ONEVirtualNetworkStatus) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEVirtualNetworkStatus.
+func (in *ONEVirtualNetworkStatus) DeepCopy() *ONEVirtualNetworkStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEVirtualNetworkStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ONEVirtualRouter) DeepCopyInto(out *ONEVirtualRouter) {
 	*out = *in
@@ -457,6 +1139,13 @@ func (in *ONEVirtualRouter) DeepCopyInto(out *ONEVirtualRouter) {
 		*out = make([]int32, len(*in))
 		copy(*out, *in)
 	}
+	if in.Listeners != nil {
+		in, out := &in.Listeners, &out.Listeners
+		*out = make([]ONEVirtualRouterListener, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ExtraContext != nil {
 		in, out := &in.ExtraContext, &out.ExtraContext
 		*out = make(map[string]string, len(*in))
@@ -475,3 +1164,23 @@ func (in *ONEVirtualRouter) DeepCopy() *ONEVirtualRouter {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ONEVirtualRouterListener) DeepCopyInto(out *ONEVirtualRouterListener) {
+	*out = *in
+	if in.TargetPort != nil {
+		in, out := &in.TargetPort, &out.TargetPort
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ONEVirtualRouterListener.
+func (in *ONEVirtualRouterListener) DeepCopy() *ONEVirtualRouterListener {
+	if in == nil {
+		return nil
+	}
+	out := new(ONEVirtualRouterListener)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -43,6 +43,7 @@ import (
 	"k8s.io/klog/v2"
 
 	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+	"github.com/OpenNebula/cluster-api-provider-opennebula/internal/cloud"
 	controllers "github.com/OpenNebula/cluster-api-provider-opennebula/internal/controller"
 	corev1 "k8s.io/api/core/v1"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
@@ -70,6 +71,11 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var machineProvisioningTimeout time.Duration
+	var machineTerminationGracePeriod time.Duration
+	var identityNamespace string
+	var oneQPS float64
+	var oneBurst int
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -81,6 +87,16 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&machineProvisioningTimeout, "machine-provisioning-timeout", 0,
+		"How long a VM may take to reach RUNNING before its ONEMachine is marked as failed. Zero disables the timeout.")
+	flag.DurationVar(&machineTerminationGracePeriod, "machine-termination-grace-period", 2*time.Minute,
+		"How long to wait for a VM to shut down gracefully before terminating it hard. Zero always terminates hard.")
+	flag.StringVar(&identityNamespace, "identity-namespace", os.Getenv("POD_NAMESPACE"),
+		"The namespace holding the Secrets referenced by ONEClusterIdentities, defaults to the controller namespace.")
+	flag.Float64Var(&oneQPS, "one-api-qps", cloud.DefaultQPS,
+		"Maximum number of XML-RPC calls per second made to every OpenNebula endpoint.")
+	flag.IntVar(&oneBurst, "one-api-burst", cloud.DefaultBurst,
+		"Maximum burst of XML-RPC calls made to every OpenNebula endpoint.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -178,16 +194,24 @@ func main() {
 		os.Exit(1)
 	}
 
+	clientsCache := cloud.NewClientsCache(cloud.WithRateLimit(oneQPS, oneBurst))
+
 	if err = (&controllers.ONEClusterReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:            mgr.GetClient(),
+		Scheme:            mgr.GetScheme(),
+		IdentityNamespace: identityNamespace,
+		ClientsCache:      clientsCache,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ONECluster")
 		os.Exit(1)
 	}
 	if err = (&controllers.ONEMachineReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:                 mgr.GetClient(),
+		Scheme:                 mgr.GetScheme(),
+		ProvisioningTimeout:    machineProvisioningTimeout,
+		TerminationGracePeriod: machineTerminationGracePeriod,
+		IdentityNamespace:      identityNamespace,
+		ClientsCache:           clientsCache,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ONEMachine")
 		os.Exit(1)
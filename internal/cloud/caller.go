@@ -0,0 +1,93 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+)
+
+const (
+	DefaultQPS   = 10
+	DefaultBurst = 20
+)
+
+// defaultBackoff spreads 4 retries over roughly 7 seconds.
+var defaultBackoff = wait.Backoff{
+	Duration: 500 * time.Millisecond,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Steps:    4,
+	Cap:      5 * time.Second,
+}
+
+// rpcCaller throttles XML-RPC calls with a (per endpoint) rate limiter, classifies
+// their errors and retries transient failures with an exponential backoff.
+type rpcCaller struct {
+	client  goca.RPCCaller
+	limiter *rate.Limiter
+	backoff wait.Backoff
+}
+
+func newRPCCaller(client goca.RPCCaller, limiter *rate.Limiter) *rpcCaller {
+	return &rpcCaller{client: client, limiter: limiter, backoff: defaultBackoff}
+}
+
+func (c *rpcCaller) CallContext(ctx context.Context, method string, args ...interface{}) (*goca.Response, error) {
+	backoff := c.backoff
+	for {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, fmt.Errorf("Failed to wait for rate limiter: %w", err)
+			}
+		}
+
+		response, err := c.client.CallContext(ctx, method, args...)
+		if err == nil {
+			return response, nil
+		}
+		err = classifyError(err)
+		if backoff.Steps < 1 || !isRetriable(method, err) {
+			return nil, err
+		}
+
+		timer := time.NewTimer(backoff.Step())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetriable only retries read-only calls, or calls OpenNebula never processed,
+// so a lost response never makes a non-idempotent call (e.g. allocate) run twice.
+func isRetriable(method string, err error) bool {
+	if !errors.Is(err, ErrTransient) {
+		return false
+	}
+	return strings.Contains(method, ".info") || errors.Is(err, errRequestDropped)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_errors "github.com/OpenNebula/one/src/oca/go/src/goca/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestIsRetriable(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		err    error
+		want   bool
+	}{
+		{
+			name:   "transient info call",
+			method: "one.vm.info",
+			err:    httpError(http.StatusBadGateway),
+			want:   true,
+		},
+		{
+			name:   "transient pool info call",
+			method: "one.vnpool.info",
+			err:    httpError(http.StatusGatewayTimeout),
+			want:   true,
+		},
+		{
+			name:   "transient allocate call",
+			method: "one.vm.allocate",
+			err:    httpError(http.StatusBadGateway),
+			want:   false,
+		},
+		{
+			name:   "dropped allocate call",
+			method: "one.vm.allocate",
+			err:    httpError(http.StatusServiceUnavailable),
+			want:   true,
+		},
+		{
+			name:   "not found info call",
+			method: "one.vm.info",
+			err:    &goca_errors.ResponseError{Code: goca_errors.OneNoExistsError},
+			want:   false,
+		},
+		{
+			name:   "auth failed info call",
+			method: "one.vm.info",
+			err:    &goca_errors.ResponseError{Code: goca_errors.OneAuthenticationError},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetriable(tt.method, classifyError(tt.err)); got != tt.want {
+				t.Fatalf("isRetriable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fakeCaller struct {
+	errs  []error
+	calls int
+}
+
+func (c *fakeCaller) CallContext(_ context.Context, _ string, _ ...interface{}) (*goca.Response, error) {
+	c.calls++
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	return &goca.Response{}, nil
+}
+
+func TestRPCCallerCallContext(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		errs      []error
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "retries transient errors",
+			method:    "one.vm.info",
+			errs:      []error{httpError(http.StatusBadGateway), httpError(http.StatusBadGateway)},
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up after the backoff steps",
+			method:    "one.vm.info",
+			errs:      []error{httpError(502), httpError(502), httpError(502), httpError(502)},
+			wantErr:   ErrTransient,
+			wantCalls: 3,
+		},
+		{
+			name:      "does not retry non-idempotent calls",
+			method:    "one.vm.allocate",
+			errs:      []error{httpError(http.StatusBadGateway)},
+			wantErr:   ErrTransient,
+			wantCalls: 1,
+		},
+		{
+			name:      "does not retry quota errors",
+			method:    "one.vm.allocate",
+			errs:      []error{&goca_errors.ResponseError{Code: goca_errors.OneAuthorizationError, Msg: "VM quota exceeded"}},
+			wantErr:   ErrQuotaExceeded,
+			wantCalls: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeCaller{errs: tt.errs}
+			caller := newRPCCaller(client, nil)
+			caller.backoff = wait.Backoff{Duration: time.Millisecond, Factor: 1.0, Steps: 2}
+
+			_, err := caller.CallContext(context.Background(), tt.method)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CallContext() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CallContext() error = %v, want %v", err, tt.wantErr)
+			}
+			if client.calls != tt.wantCalls {
+				t.Fatalf("CallContext() calls = %d, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -43,7 +43,7 @@ func (c *Cleanup) getVirtualRouterName() string {
 
 func (c *Cleanup) DeleteLBVirtualRouter() error {
 	vrID, err := c.ctrl.VirtualRouterByName(c.getVirtualRouterName())
-	if err != nil && err.Error() != "resource not found" {
+	if err != nil && !isNotFound(err) {
 		return err
 	}
 	if vrID < 0 {
@@ -59,7 +59,7 @@ func (c *Cleanup) getVRReservationName() string {
 
 func (c *Cleanup) DeleteVRReservation() error {
 	vnID, err := c.ctrl.VirtualNetworks().ByName(c.getVRReservationName())
-	if err != nil && err.Error() != "resource not found" {
+	if err != nil && !isNotFound(err) {
 		return err
 	}
 	if vnID < 0 {
@@ -75,7 +75,7 @@ func (c *Cleanup) getLBReservationName() string {
 
 func (c *Cleanup) DeleteLBReservation() error {
 	vnID, err := c.ctrl.VirtualNetworks().ByName(c.getLBReservationName())
-	if err != nil && err.Error() != "resource not found" {
+	if err != nil && !isNotFound(err) {
 		return err
 	}
 	if vnID < 0 {
@@ -83,9 +83,12 @@ func (c *Cleanup) DeleteLBReservation() error {
 	}
 
 	vn, err := c.ctrl.VirtualNetwork(vnID).Info(true)
-	if err != nil {
+	if isNotFound(err) {
 		return nil
 	}
+	if err != nil {
+		return fmt.Errorf("Failed to fetch LB reservation: %w", err)
+	}
 	for _, ar := range vn.ARs {
 		release := &goca_dyn.Vector{XMLName: xml.Name{Local: "LEASES"}}
 		release.AddPair("IP", ar.IP)
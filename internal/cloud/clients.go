@@ -18,10 +18,21 @@ package cloud
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
 
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,19 +43,195 @@ import (
 )
 
 type Clients struct {
-	RPC2 *goca.Client
+	RPC2 goca.RPCCaller
 }
 
-func NewClients(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluster) (*Clients, error) {
-	rpc2, err := newRPC2(ctx, c, oneCluster)
+// NewClients builds OpenNebula clients from the credentials of the ONECluster,
+// identityNamespace is where the Secrets referenced by ONEClusterIdentities live.
+func NewClients(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluster, identityNamespace string) (*Clients, error) {
+	secret, err := getCredentialsSecret(ctx, c, oneCluster, identityNamespace)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Clients{RPC2: rpc2}, nil
+	return newClientsFromSecret(secret, rate.NewLimiter(DefaultQPS, DefaultBurst))
 }
 
-func newRPC2(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluster) (*goca.Client, error) {
+func newClientsFromSecret(secret *corev1.Secret, limiter *rate.Limiter) (*Clients, error) {
+	rpc2, err := newRPC2(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Clients{RPC2: newRPCCaller(rpc2, limiter)}, nil
+}
+
+func newRPC2(secret *corev1.Secret) (*goca.Client, error) {
+	httpClient, err := newHTTPClient(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: A nil httpClient makes goca fall back to its default client.
+	return goca.NewClient(goca.OneConfig{
+		Endpoint: string(secret.Data["ONE_XMLRPC"]),
+		Token:    string(secret.Data["ONE_AUTH"]),
+	}, httpClient), nil
+}
+
+// newHTTPClient builds the XML-RPC http client from the optional Secret keys:
+// ONE_CA_BUNDLE (PEM), ONE_INSECURE_SKIP_VERIFY, ONE_HTTP_PROXY and ONE_TIMEOUT (e.g. "30s").
+func newHTTPClient(secret *corev1.Secret) (*http.Client, error) {
+	caBundle := secret.Data["ONE_CA_BUNDLE"]
+	insecureSkipVerify := string(secret.Data["ONE_INSECURE_SKIP_VERIFY"])
+	proxyURL := string(secret.Data["ONE_HTTP_PROXY"])
+	timeout := string(secret.Data["ONE_TIMEOUT"])
+	if len(caBundle) == 0 && insecureSkipVerify == "" && proxyURL == "" && timeout == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(caBundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("Failed to parse ONE_CA_BUNDLE: no valid PEM certificates found")
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	if insecureSkipVerify != "" {
+		skip, err := strconv.ParseBool(insecureSkipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse ONE_INSECURE_SKIP_VERIFY: %w", err)
+		}
+		tlsConfig.InsecureSkipVerify = skip
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if proxyURL != "" {
+		proxy, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse ONE_HTTP_PROXY: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+
+	httpClient := &http.Client{Transport: transport}
+	if timeout != "" {
+		requestTimeout, err := time.ParseDuration(timeout)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to parse ONE_TIMEOUT: %w", err)
+		}
+		httpClient.Timeout = requestTimeout
+	}
+
+	return httpClient, nil
+}
+
+// ClientsCache keeps the clients of every ONECluster, so they are only rebuilt
+// when the credentials Secret changes (its resourceVersion) or is invalidated.
+// Clients talking to the same XML-RPC endpoint share a single rate limiter.
+type ClientsCache struct {
+	mu       sync.Mutex
+	entries  map[types.UID]clientsCacheEntry
+	limiters map[string]*rate.Limiter
+	qps      rate.Limit
+	burst    int
+}
+
+type clientsCacheEntry struct {
+	secretUID             types.UID
+	secretResourceVersion string
+	clients               *Clients
+}
+
+type ClientsCacheOption func(*ClientsCache)
+
+// WithRateLimit limits the calls per second made to every OpenNebula endpoint.
+func WithRateLimit(qps float64, burst int) ClientsCacheOption {
+	return func(cc *ClientsCache) {
+		cc.qps = rate.Limit(qps)
+		cc.burst = burst
+	}
+}
+
+func NewClientsCache(options ...ClientsCacheOption) *ClientsCache {
+	cc := &ClientsCache{
+		entries:  map[types.UID]clientsCacheEntry{},
+		limiters: map[string]*rate.Limiter{},
+		qps:      DefaultQPS,
+		burst:    DefaultBurst,
+	}
+	for _, option := range options {
+		option(cc)
+	}
+	return cc
+}
+
+// Get returns the cached clients of the ONECluster, a nil cache always builds new clients.
+func (cc *ClientsCache) Get(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluster, identityNamespace string) (*Clients, error) {
+	if cc == nil {
+		return NewClients(ctx, c, oneCluster, identityNamespace)
+	}
+
+	secret, err := getCredentialsSecret(ctx, c, oneCluster, identityNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	entry, ok := cc.entries[oneCluster.UID]
+	if ok && entry.secretUID == secret.UID && entry.secretResourceVersion == secret.ResourceVersion {
+		return entry.clients, nil
+	}
+
+	endpoint := string(secret.Data["ONE_XMLRPC"])
+	limiter, ok := cc.limiters[endpoint]
+	if !ok {
+		limiter = rate.NewLimiter(cc.qps, cc.burst)
+		cc.limiters[endpoint] = limiter
+	}
+
+	clients, err := newClientsFromSecret(secret, limiter)
+	if err != nil {
+		return nil, err
+	}
+	entry = clientsCacheEntry{
+		secretUID:             secret.UID,
+		secretResourceVersion: secret.ResourceVersion,
+		clients:               clients,
+	}
+	cc.entries[oneCluster.UID] = entry
+	return entry.clients, nil
+}
+
+// Invalidate drops the cached clients of the ONECluster.
+func (cc *ClientsCache) Invalidate(clusterUID types.UID) {
+	if cc == nil {
+		return
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	delete(cc.entries, clusterUID)
+}
+
+func getCredentialsSecret(
+	ctx context.Context, c client.Client,
+	oneCluster *infrav1.ONECluster, identityNamespace string) (*corev1.Secret, error) {
+
+	if oneCluster.Spec.IdentityRef != nil {
+		return getIdentitySecret(ctx, c, oneCluster, identityNamespace)
+	}
+	return getClusterSecret(ctx, c, oneCluster)
+}
+
+func getClusterSecret(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluster) (*corev1.Secret, error) {
 	var secret corev1.Secret
 	key := client.ObjectKey{
 		Namespace: oneCluster.Namespace,
@@ -54,18 +241,83 @@ func newRPC2(ctx context.Context, c client.Client, oneCluster *infrav1.ONECluste
 		return nil, fmt.Errorf("Failed to get secret: %w", err)
 	}
 
-	secret.SetOwnerReferences(util.EnsureOwnerRef(secret.OwnerReferences, metav1.OwnerReference{
+	ownerRef := metav1.OwnerReference{
 		APIVersion: infrav1.GroupVersion.String(),
 		Kind:       "ONECluster",
 		Name:       oneCluster.Name,
 		UID:        oneCluster.UID,
-	}))
-	if err := c.Update(ctx, &secret); err != nil {
-		return nil, fmt.Errorf("Failed to set ownerReference to secret: %w", err)
+	}
+	if !util.HasOwnerRef(secret.OwnerReferences, ownerRef) {
+		patchBase := client.MergeFrom(secret.DeepCopy())
+		secret.SetOwnerReferences(util.EnsureOwnerRef(secret.OwnerReferences, ownerRef))
+		if err := c.Patch(ctx, &secret, patchBase); err != nil {
+			return nil, fmt.Errorf("Failed to set ownerReference to secret: %w", err)
+		}
 	}
 
-	return goca.NewDefaultClient(goca.OneConfig{
-		Endpoint: string(secret.Data["ONE_XMLRPC"]),
-		Token:    string(secret.Data["ONE_AUTH"]),
-	}), nil
+	return &secret, nil
+}
+
+// getIdentitySecret resolves the Secret of the referenced ONEClusterIdentity, the Secret
+// is shared by many clusters so (unlike getClusterSecret) no owner reference is set.
+func getIdentitySecret(
+	ctx context.Context, c client.Client,
+	oneCluster *infrav1.ONECluster, identityNamespace string) (*corev1.Secret, error) {
+
+	if identityNamespace == "" {
+		return nil, fmt.Errorf("Failed to get identity: identity namespace is not configured")
+	}
+
+	var identity infrav1.ONEClusterIdentity
+	if err := c.Get(ctx, client.ObjectKey{Name: oneCluster.Spec.IdentityRef.Name}, &identity); err != nil {
+		return nil, fmt.Errorf("Failed to get identity: %w", err)
+	}
+
+	allowed, err := identityAllowsNamespace(ctx, c, &identity, oneCluster.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		return nil, fmt.Errorf("ONEClusterIdentity %s is not allowed in namespace %s", identity.Name, oneCluster.Namespace)
+	}
+
+	var secret corev1.Secret
+	key := client.ObjectKey{
+		Namespace: identityNamespace,
+		Name:      identity.Spec.SecretName,
+	}
+	if err := c.Get(ctx, key, &secret); err != nil {
+		return nil, fmt.Errorf("Failed to get identity secret: %w", err)
+	}
+
+	return &secret, nil
+}
+
+func identityAllowsNamespace(
+	ctx context.Context, c client.Client,
+	identity *infrav1.ONEClusterIdentity, namespace string) (bool, error) {
+
+	allowedNamespaces := identity.Spec.AllowedNamespaces
+	if allowedNamespaces == nil {
+		return false, nil
+	}
+	if len(allowedNamespaces.NamespaceList) == 0 && allowedNamespaces.Selector == nil {
+		return true, nil
+	}
+	if slices.Contains(allowedNamespaces.NamespaceList, namespace) {
+		return true, nil
+	}
+	if allowedNamespaces.Selector == nil {
+		return false, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(allowedNamespaces.Selector)
+	if err != nil {
+		return false, fmt.Errorf("Failed to parse identity namespace selector: %w", err)
+	}
+	var ns corev1.Namespace
+	if err := c.Get(ctx, client.ObjectKey{Name: namespace}, &ns); err != nil {
+		return false, fmt.Errorf("Failed to get namespace: %w", err)
+	}
+	return selector.Matches(labels.Set(ns.GetLabels())), nil
 }
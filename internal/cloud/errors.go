@@ -0,0 +1,133 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+
+	goca_errors "github.com/OpenNebula/one/src/oca/go/src/goca/errors"
+	"github.com/kolo/xmlrpc"
+)
+
+// Kinds of OpenNebula errors, match them with errors.Is().
+var (
+	// ErrNotFound is returned (wrapped) when the requested OpenNebula resource does not exist.
+	ErrNotFound = errors.New("resource not found")
+
+	ErrAuthFailed     = errors.New("authentication or authorization failed")
+	ErrQuotaExceeded  = errors.New("quota exceeded")
+	ErrTransient      = errors.New("transient error")
+	ErrConflict       = errors.New("conflict")
+	errRequestDropped = errors.New("request was not processed")
+)
+
+// xmlrpc-c fault codes worth retrying (network, timeout and limit exceeded errors).
+var transientFaultCodes = []int{-504, -505, -509}
+
+// Error is an OpenNebula error classified into one of the error kinds.
+type Error struct {
+	Kind error
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// classifyError wraps goca's ClientError / ResponseError into an *Error
+// of the matching kind, unknown errors are returned unchanged.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var cloudErr *Error
+	if errors.As(err, &cloudErr) {
+		return err
+	}
+	if kind := errorKind(err); kind != nil {
+		return &Error{Kind: kind, Err: err}
+	}
+	return err
+}
+
+func errorKind(err error) error {
+	var respErr *goca_errors.ResponseError
+	if errors.As(err, &respErr) {
+		msg := strings.ToLower(respErr.Msg)
+		switch {
+		// NOTE: quota failures are reported as AUTHORIZATION errors.
+		case strings.Contains(msg, "quota"):
+			return ErrQuotaExceeded
+		case respErr.Code == goca_errors.OneNoExistsError:
+			return ErrNotFound
+		case respErr.Code == goca_errors.OneAuthenticationError, respErr.Code == goca_errors.OneAuthorizationError:
+			return ErrAuthFailed
+		case respErr.Code == goca_errors.OneLockedError, strings.Contains(msg, "already taken"):
+			return ErrConflict
+		}
+		return nil
+	}
+
+	var clientErr *goca_errors.ClientError
+	if errors.As(err, &clientErr) {
+		switch clientErr.Code {
+		case goca_errors.ClientReqHTTP:
+			var opErr *net.OpError
+			if errors.As(clientErr.Err, &opErr) && opErr.Op == "dial" {
+				return errors.Join(ErrTransient, errRequestDropped)
+			}
+			return ErrTransient
+		case goca_errors.ClientRespHTTP:
+			if clientErr.HttpResp == nil {
+				return ErrTransient
+			}
+			switch statusCode := clientErr.HttpResp.StatusCode; {
+			case statusCode == http.StatusUnauthorized, statusCode == http.StatusForbidden:
+				return ErrAuthFailed
+			case statusCode == http.StatusTooManyRequests, statusCode == http.StatusServiceUnavailable:
+				return errors.Join(ErrTransient, errRequestDropped)
+			case statusCode >= 500, statusCode/100 == 2:
+				return ErrTransient
+			}
+		case goca_errors.ClientRespXMLRPCFault:
+			var fault xmlrpc.FaultError
+			if errors.As(clientErr.Err, &fault) && slices.Contains(transientFaultCodes, fault.Code) {
+				return ErrTransient
+			}
+		}
+		return nil
+	}
+
+	// NOTE: goca's ByName() lookups return plain "resource not found" errors.
+	if err.Error() == ErrNotFound.Error() {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func isNotFound(err error) bool {
+	return errors.Is(classifyError(err), ErrNotFound)
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	goca_errors "github.com/OpenNebula/one/src/oca/go/src/goca/errors"
+	"github.com/kolo/xmlrpc"
+)
+
+func httpError(statusCode int) error {
+	return &goca_errors.ClientError{
+		Code:     goca_errors.ClientRespHTTP,
+		Msg:      fmt.Sprintf("http status code: %d", statusCode),
+		HttpResp: &http.Response{StatusCode: statusCode},
+	}
+}
+
+func faultError(code int) error {
+	return &goca_errors.ClientError{
+		Code: goca_errors.ClientRespXMLRPCFault,
+		Msg:  "server response",
+		Err:  xmlrpc.FaultError{Code: code, String: "fault"},
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	dialErr := &goca_errors.ClientError{
+		Code: goca_errors.ClientReqHTTP,
+		Msg:  "http make request",
+		Err:  &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+	}
+	readErr := &goca_errors.ClientError{
+		Code: goca_errors.ClientReqHTTP,
+		Msg:  "http make request",
+		Err:  &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")},
+	}
+
+	tests := []struct {
+		name        string
+		err         error
+		wantKind    error
+		wantDropped bool
+	}{
+		{
+			name:     "no exists",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneNoExistsError, Msg: "Error getting VM [42]."},
+			wantKind: ErrNotFound,
+		},
+		{
+			name:     "authentication",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneAuthenticationError, Msg: "User couldn't be authenticated"},
+			wantKind: ErrAuthFailed,
+		},
+		{
+			name:     "authorization",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneAuthorizationError, Msg: "Not authorized to perform CREATE VM"},
+			wantKind: ErrAuthFailed,
+		},
+		{
+			name:     "quota reported as authorization",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneAuthorizationError, Msg: "User [5] : limit of 2 reached for VMS quota"},
+			wantKind: ErrQuotaExceeded,
+		},
+		{
+			name:     "locked",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneLockedError, Msg: "VM is locked"},
+			wantKind: ErrConflict,
+		},
+		{
+			name:     "name taken",
+			err:      &goca_errors.ResponseError{Code: goca_errors.OneAllocateError, Msg: "NAME is already taken by IMAGE 5."},
+			wantKind: ErrConflict,
+		},
+		{
+			name: "internal",
+			err:  &goca_errors.ResponseError{Code: goca_errors.OneInternalError, Msg: "Internal error"},
+		},
+		{
+			name:     "wrapped response error",
+			err:      fmt.Errorf("Failed to fetch VM: %w", &goca_errors.ResponseError{Code: goca_errors.OneNoExistsError}),
+			wantKind: ErrNotFound,
+		},
+		{
+			name:        "dial error",
+			err:         dialErr,
+			wantKind:    ErrTransient,
+			wantDropped: true,
+		},
+		{
+			name:     "read error",
+			err:      readErr,
+			wantKind: ErrTransient,
+		},
+		{
+			name:        "too many requests",
+			err:         httpError(http.StatusTooManyRequests),
+			wantKind:    ErrTransient,
+			wantDropped: true,
+		},
+		{
+			name:        "service unavailable",
+			err:         httpError(http.StatusServiceUnavailable),
+			wantKind:    ErrTransient,
+			wantDropped: true,
+		},
+		{
+			name:     "bad gateway",
+			err:      httpError(http.StatusBadGateway),
+			wantKind: ErrTransient,
+		},
+		{
+			name:     "unauthorized",
+			err:      httpError(http.StatusUnauthorized),
+			wantKind: ErrAuthFailed,
+		},
+		{
+			name: "http not found",
+			err:  httpError(http.StatusNotFound),
+		},
+		{
+			name:     "xmlrpc timeout fault",
+			err:      faultError(-505),
+			wantKind: ErrTransient,
+		},
+		{
+			name: "xmlrpc type fault",
+			err:  faultError(-501),
+		},
+		{
+			name:     "goca lookup by name",
+			err:      errors.New("resource not found"),
+			wantKind: ErrNotFound,
+		},
+		{
+			name: "unknown",
+			err:  errors.New("multiple resources with that name"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind := errorKind(tt.err)
+			if tt.wantKind == nil {
+				if kind != nil {
+					t.Fatalf("errorKind() = %v, want nil", kind)
+				}
+				return
+			}
+			if !errors.Is(kind, tt.wantKind) {
+				t.Fatalf("errorKind() = %v, want %v", kind, tt.wantKind)
+			}
+			if dropped := errors.Is(kind, errRequestDropped); dropped != tt.wantDropped {
+				t.Fatalf("errorKind() dropped = %v, want %v", dropped, tt.wantDropped)
+			}
+
+			err := classifyError(tt.err)
+			if !errors.Is(err, tt.wantKind) || err.Error() != tt.err.Error() {
+				t.Fatalf("classifyError() = %v, want %v wrapping %v", err, tt.wantKind, tt.err)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package cloud
 
 import (
 	"encoding/xml"
+	"strings"
 	"unsafe"
 
 	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
@@ -25,24 +26,59 @@ import (
 	goca_vm "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/vm"
 )
 
-func getNICs(maybeTemplate interface{}) (nics []*goca_dyn.Vector) {
-	nics = make([]*goca_dyn.Vector, 0, 1)
+// getOwners returns the cluster UIDs listed in CAPONE_OWNERS.
+func getOwners(template *goca_dyn.Template) []string {
+	owners := []string{}
+	if existingOwners, err := template.GetStr("CAPONE_OWNERS"); err == nil {
+		for _, owner := range strings.Split(existingOwners, ",") {
+			if owner = strings.TrimSpace(owner); owner != "" {
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+func getNICs(maybeTemplate interface{}) []*goca_dyn.Vector {
+	return getVectors(maybeTemplate, "NIC")
+}
+
+func ensureNIC(maybeTemplate interface{}, index int) *goca_dyn.Vector {
+	return ensureVector(maybeTemplate, "NIC", index)
+}
+
+func getDisks(maybeTemplate interface{}) []*goca_dyn.Vector {
+	return getVectors(maybeTemplate, "DISK")
+}
 
-	var template *goca_dyn.Template
+func ensureDisk(maybeTemplate interface{}, index int) *goca_dyn.Vector {
+	return ensureVector(maybeTemplate, "DISK", index)
+}
+
+func asDynamicTemplate(maybeTemplate interface{}) *goca_dyn.Template {
 	switch v := maybeTemplate.(type) {
 	case *goca_vm.Template:
-		template = (*goca_dyn.Template)(unsafe.Pointer(v))
+		return (*goca_dyn.Template)(unsafe.Pointer(v))
 	case *goca_vr.Template:
-		template = (*goca_dyn.Template)(unsafe.Pointer(v))
+		return (*goca_dyn.Template)(unsafe.Pointer(v))
 	default:
+		return nil
+	}
+}
+
+func getVectors(maybeTemplate interface{}, name string) (vectors []*goca_dyn.Vector) {
+	vectors = make([]*goca_dyn.Vector, 0, 1)
+
+	template := asDynamicTemplate(maybeTemplate)
+	if template == nil {
 		return
 	}
 
-	for _, maybeNIC := range template.Elements {
-		// NOTE: do NOT use AddNIC()
-		if v, ok := maybeNIC.(*goca_dyn.Vector); ok {
-			if v.XMLName.Local == "NIC" {
-				nics = append(nics, v)
+	for _, maybeVector := range template.Elements {
+		// NOTE: do NOT use AddNIC() / AddDisk()
+		if v, ok := maybeVector.(*goca_dyn.Vector); ok {
+			if v.XMLName.Local == name {
+				vectors = append(vectors, v)
 			}
 		}
 	}
@@ -50,41 +86,27 @@ func getNICs(maybeTemplate interface{}) (nics []*goca_dyn.Vector) {
 	return
 }
 
-func ensureNIC(maybeTemplate interface{}, index int) *goca_dyn.Vector {
+func ensureVector(maybeTemplate interface{}, name string, index int) *goca_dyn.Vector {
 	if index < 0 {
 		return nil
 	}
 
-	nics := make([]*goca_dyn.Vector, 0, 1)
-
-	var template *goca_dyn.Template
-	switch v := maybeTemplate.(type) {
-	case *goca_vm.Template:
-		template = (*goca_dyn.Template)(unsafe.Pointer(v))
-	case *goca_vr.Template:
-		template = (*goca_dyn.Template)(unsafe.Pointer(v))
-	default:
+	template := asDynamicTemplate(maybeTemplate)
+	if template == nil {
 		return nil
 	}
 
-	for _, maybeNIC := range template.Elements {
-		// NOTE: do NOT use AddNIC()
-		if v, ok := maybeNIC.(*goca_dyn.Vector); ok {
-			if v.XMLName.Local == "NIC" {
-				nics = append(nics, v)
-			}
-		}
-	}
+	vectors := getVectors(maybeTemplate, name)
 
-	if index < len(nics) {
-		return nics[index]
+	if index < len(vectors) {
+		return vectors[index]
 	} else {
-		var nicVec *goca_dyn.Vector
-		for k := len(nics); k <= index; k++ {
-			nicVec = &goca_dyn.Vector{XMLName: xml.Name{Local: "NIC"}}
-			template.Elements = append(template.Elements, nicVec)
+		var vec *goca_dyn.Vector
+		for k := len(vectors); k <= index; k++ {
+			vec = &goca_dyn.Vector{XMLName: xml.Name{Local: name}}
+			template.Elements = append(template.Elements, vec)
 		}
-		return nicVec
+		return vec
 	}
 }
 
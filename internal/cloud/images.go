@@ -17,55 +17,189 @@ limitations under the License.
 package cloud
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_params "github.com/OpenNebula/one/src/oca/go/src/goca/parameters"
 	goca_image "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/image"
 )
 
 type Images struct {
-	ctrl *goca.Controller
+	ctrl       *goca.Controller
+	clusterUID string
 }
 
-func NewImages(clients *Clients) (*Images, error) {
+func NewImages(clients *Clients, clusterUID string) (*Images, error) {
 	if clients == nil {
 		return nil, fmt.Errorf("clients reference is nil")
 	}
 
-	return &Images{ctrl: goca.NewController(clients.RPC2)}, nil
+	return &Images{ctrl: goca.NewController(clients.RPC2), clusterUID: clusterUID}, nil
 }
 
-func (i *Images) CreateImage(imageName, imageContent string, datastoreId uint) error {
-	existingImageID, err := i.ctrl.Images().ByName(imageName)
-	if err != nil && err.Error() != "resource not found" {
-		return err
+// CreateImage makes sure an image holding imageContent exists and returns its name.
+// Image versions are keyed by imageName and the hash of their content, the first
+// version is named imageName and later ones are suffixed with their content hash.
+// Images are never renamed, so a name keeps pointing to the same content. An image
+// named imageName without a content hash (e.g. imported by an earlier release) is
+// adopted as the version holding imageContent.
+// Image versions record the UIDs of all the clusters using them in CAPONE_OWNERS.
+func (i *Images) CreateImage(imageName, imageContent string, datastoreId uint) (string, error) {
+	contentHash := hashImageContent(imageContent)
+
+	pool, err := i.ctrl.Images().Info()
+	if err != nil {
+		return "", fmt.Errorf("Failed to list images: %w", err)
+	}
+
+	var namedImage *goca_image.Image
+	for idx := range pool.Images {
+		image := &pool.Images[idx]
+		name, _ := image.Template.GetStr("CAPONE_IMAGE_NAME")
+		hash, _ := image.Template.GetStr("CAPONE_CONTENT_HASH")
+		if name == imageName && hash == contentHash {
+			return image.Name, i.addImageOwner(image)
+		}
+		if image.Name == imageName {
+			namedImage = image
+		}
+	}
+
+	if namedImage != nil {
+		if hash, _ := namedImage.Template.GetStr("CAPONE_CONTENT_HASH"); hash == "" {
+			update := goca_dyn.NewTemplate()
+			update.AddPair("CAPONE_IMAGE_NAME", imageName)
+			update.AddPair("CAPONE_CONTENT_HASH", contentHash)
+			update.AddPair("CAPONE_OWNERS", i.clusterUID)
+			if err := i.ctrl.Image(namedImage.ID).Update(update.String(), goca_params.Merge); err != nil {
+				return "", fmt.Errorf("Failed to update image: %w", err)
+			}
+			return imageName, nil
+		}
+	}
+
+	resolvedName := imageName
+	if namedImage != nil {
+		resolvedName = fmt.Sprintf("%s-%s", imageName, contentHash[:12])
+	}
+	imageSpec := fmt.Sprintf("NAME = \"%s\"\nCAPONE_IMAGE_NAME = \"%s\"\nCAPONE_CONTENT_HASH = \"%s\"\nCAPONE_OWNERS = \"%s\"\n%s",
+		resolvedName, imageName, contentHash, i.clusterUID, imageContent)
+	if _, err = i.ctrl.Images().Create(imageSpec, datastoreId); err != nil {
+		return "", fmt.Errorf("Failed to create image: %w", err)
+	}
+
+	return resolvedName, nil
+}
+
+// DeleteImage removes the cluster from the owners of all versions of the image and
+// deletes the versions no other cluster owns. It refuses to delete anything while
+// any of those versions is still used by VMs and reports how many VMs use them.
+func (i *Images) DeleteImage(imageName string) (int, error) {
+	pool, err := i.ctrl.Images().Info()
+	if err != nil {
+		return 0, fmt.Errorf("Failed to list images: %w", err)
+	}
+
+	type imageOwners struct {
+		imageID int
+		owners  []string
+	}
+	imageIDs := []int{}
+	updates := []imageOwners{}
+	usedByVMs := 0
+	for _, image := range pool.Images {
+		if name, _ := image.Template.GetStr("CAPONE_IMAGE_NAME"); name != imageName {
+			continue
+		}
+		owners := getOwners(&image.Template.Template)
+		idx := slices.Index(owners, i.clusterUID)
+		if idx < 0 {
+			continue
+		}
+		if owners = slices.Delete(owners, idx, idx+1); len(owners) > 0 {
+			updates = append(updates, imageOwners{imageID: image.ID, owners: owners})
+			continue
+		}
+		imageIDs = append(imageIDs, image.ID)
+		usedByVMs += len(image.VMs.ID)
+	}
+	if usedByVMs > 0 {
+		return usedByVMs, nil
 	}
 
-	if existingImageID < 0 {
-		imageSpec := fmt.Sprintf("NAME = \"%s\"\n%s", imageName, imageContent)
-		if _, err = i.ctrl.Images().Create(imageSpec, datastoreId); err != nil {
-			return fmt.Errorf("Failed to create image: %w", err)
+	for _, update := range updates {
+		if err := i.setImageOwners(update.imageID, update.owners); err != nil {
+			return 0, err
+		}
+	}
+	for _, imageID := range imageIDs {
+		if err := i.ctrl.Image(imageID).Delete(); err != nil {
+			return 0, fmt.Errorf("Failed to delete image: %w", err)
 		}
 	}
+	return 0, nil
+}
+
+func (i *Images) addImageOwner(image *goca_image.Image) error {
+	owners := getOwners(&image.Template.Template)
+	if slices.Contains(owners, i.clusterUID) {
+		return nil
+	}
+	return i.setImageOwners(image.ID, append(owners, i.clusterUID))
+}
 
+func (i *Images) setImageOwners(imageID int, owners []string) error {
+	update := goca_dyn.NewTemplate()
+	update.AddPair("CAPONE_OWNERS", strings.Join(owners, ","))
+	if err := i.ctrl.Image(imageID).Update(update.String(), goca_params.Merge); err != nil {
+		return fmt.Errorf("Failed to update image: %w", err)
+	}
 	return nil
 }
 
-func (i *Images) ImageReady(imageName string) (bool, error) {
+func hashImageContent(imageContent string) string {
+	hash := sha256.Sum256([]byte(imageContent))
+	return hex.EncodeToString(hash[:])
+}
+
+type ImageState struct {
+	ID         int
+	State      string
+	Ready      bool
+	Failed     bool
+	Error      string
+	Registered time.Time
+}
+
+func (i *Images) ImageState(imageName string) (*ImageState, error) {
 	existingImageID, err := i.ctrl.Images().ByName(imageName)
 	if err != nil {
-		return false, fmt.Errorf("Failed to find Image template: %s, %w", imageName, err)
+		return nil, fmt.Errorf("Failed to find Image template: %s, %w", imageName, err)
 	}
 
 	image, err := i.ctrl.Image(existingImageID).Info(true)
 	if err != nil {
-		return false, fmt.Errorf("Failed to get Image info: %w", err)
+		return nil, fmt.Errorf("Failed to get Image info: %w", err)
 	}
 
 	state, err := image.State()
 	if err != nil {
-		return false, fmt.Errorf("Failed to get Image state: %w", err)
+		return nil, fmt.Errorf("Failed to get Image state: %w", err)
 	}
 
-	return state == goca_image.Ready || state == goca_image.Used, nil
+	imageState := &ImageState{
+		ID:         image.ID,
+		State:      state.String(),
+		Ready:      state == goca_image.Ready || state == goca_image.Used,
+		Failed:     state == goca_image.Error,
+		Registered: time.Unix(int64(image.RegTime), 0),
+	}
+	imageState.Error, _ = image.Template.GetStr("ERROR")
+	return imageState, nil
 }
@@ -0,0 +1,225 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/netip"
+	"slices"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_vn "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/virtualnetwork"
+)
+
+// Lease is a single address put on hold in a virtual network, so OpenNebula
+// never hands it over to a VM (e.g. a kube-vip control-plane VIP).
+type Lease struct {
+	ctrl        *goca.Controller
+	NetworkID   int
+	NetworkName string
+	IP          string
+	// Held is set by Hold when the address is on hold in the network.
+	Held bool
+	// Picked is set by Hold when it picked and held a free address itself.
+	Picked bool
+}
+
+type LeaseOption func(*Lease)
+
+func WithLeaseNetworkName(name string) LeaseOption {
+	return func(l *Lease) {
+		l.NetworkName = name
+	}
+}
+
+func WithLeaseNetworkID(networkID int) LeaseOption {
+	return func(l *Lease) {
+		l.NetworkID = networkID
+	}
+}
+
+func WithLeaseIP(ip string) LeaseOption {
+	return func(l *Lease) {
+		l.IP = ip
+	}
+}
+
+func NewLease(clients *Clients, options ...LeaseOption) (*Lease, error) {
+	if clients == nil {
+		return nil, fmt.Errorf("clients reference is nil")
+	}
+
+	l := &Lease{ctrl: goca.NewController(clients.RPC2), NetworkID: -1}
+	for _, option := range options {
+		option(l)
+	}
+	return l, nil
+}
+
+func (l *Lease) resolveNetwork() (*goca_vn.VirtualNetwork, error) {
+	if l.NetworkID < 0 {
+		vnID, err := l.ctrl.VirtualNetworks().ByName(l.NetworkName)
+		if isNotFound(err) {
+			return nil, fmt.Errorf("Failed to find network: %w", ErrNotFound)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to find network: %w", err)
+		}
+		l.NetworkID = vnID
+	}
+
+	vn, err := l.ctrl.VirtualNetwork(l.NetworkID).Info(true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to fetch network: %w", err)
+	}
+	return vn, nil
+}
+
+// Hold puts l.IP on hold, or picks the first free address when l.IP is empty.
+// Holding an address that is already on hold is a no-op. An address outside
+// every address range of the network is managed externally and left alone.
+func (l *Lease) Hold() error {
+	l.Held, l.Picked = false, false
+
+	vn, err := l.resolveNetwork()
+	if err != nil {
+		return err
+	}
+
+	if addr, err := netip.ParseAddr(l.IP); err == nil {
+		l.IP = addr.String()
+		if !inAddressRanges(vn, addr) {
+			return nil
+		}
+	}
+
+	picked := l.IP == ""
+	if picked {
+		ip, err := firstFreeAddress(vn)
+		if err != nil {
+			return err
+		}
+		l.IP = ip
+	} else if lease := findLease(vn, l.IP); lease != nil {
+		if lease.VM == -1 {
+			l.Held = true
+			return nil
+		}
+		return fmt.Errorf("Address %s is already in use in network %s", l.IP, vn.Name)
+	}
+
+	hold := &goca_dyn.Vector{XMLName: xml.Name{Local: "LEASES"}}
+	hold.AddPair(leaseAddressKey(l.IP), l.IP)
+	if err := l.ctrl.VirtualNetwork(l.NetworkID).Hold(hold.String()); err != nil {
+		return fmt.Errorf("Failed to hold lease: %w", err)
+	}
+	l.Held, l.Picked = true, picked
+	return nil
+}
+
+// Release frees the address put on hold, if it is still on hold.
+func (l *Lease) Release() error {
+	if l.IP == "" {
+		return nil
+	}
+
+	vn, err := l.resolveNetwork()
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if lease := findLease(vn, l.IP); lease == nil || lease.VM != -1 {
+		return nil
+	}
+
+	release := &goca_dyn.Vector{XMLName: xml.Name{Local: "LEASES"}}
+	release.AddPair(leaseAddressKey(l.IP), l.IP)
+	if err := l.ctrl.VirtualNetwork(l.NetworkID).Release(release.String()); err != nil {
+		return fmt.Errorf("Failed to release lease: %w", err)
+	}
+	return nil
+}
+
+func leaseAddressKey(ip string) string {
+	if addr, err := netip.ParseAddr(ip); err == nil && addr.Is6() {
+		return "IP6"
+	}
+	return "IP"
+}
+
+func findLease(vn *goca_vn.VirtualNetwork, ip string) *goca_vn.Lease {
+	for _, ar := range vn.ARs {
+		for idx := range ar.Leases {
+			lease := &ar.Leases[idx]
+			if slices.Contains([]string{lease.IP, lease.IP6, lease.IP6Global, lease.IP6ULA}, ip) {
+				return lease
+			}
+		}
+	}
+	return nil
+}
+
+// inAddressRanges reports whether addr falls inside an IPv4 or static IPv6 range of vn.
+func inAddressRanges(vn *goca_vn.VirtualNetwork, addr netip.Addr) bool {
+	for _, ar := range vn.ARs {
+		first := ar.IP
+		if addr.Is6() {
+			first = ar.IP6
+		}
+		start, err := netip.ParseAddr(first)
+		if err != nil || start.BitLen() != addr.BitLen() || addr.Less(start) {
+			continue
+		}
+		offset := new(big.Int).Sub(new(big.Int).SetBytes(addr.AsSlice()), new(big.Int).SetBytes(start.AsSlice()))
+		if offset.Cmp(big.NewInt(int64(ar.Size))) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// firstFreeAddress only considers IPv4 and static IPv6 ranges,
+// SLAAC addresses are derived from MACs and cannot be held upfront.
+func firstFreeAddress(vn *goca_vn.VirtualNetwork) (string, error) {
+	for _, ar := range vn.ARs {
+		first := ar.IP
+		if first == "" {
+			first = ar.IP6
+		}
+		addr, err := netip.ParseAddr(first)
+		if err != nil {
+			continue
+		}
+		used := map[string]bool{}
+		for _, lease := range ar.Leases {
+			used[lease.IP] = true
+			used[lease.IP6] = true
+		}
+		for i := 0; i < ar.Size && addr.IsValid(); i, addr = i+1, addr.Next() {
+			if !used[addr.String()] {
+				return addr.String(), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("No free address left in network %s", vn.Name)
+}
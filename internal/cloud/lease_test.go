@@ -0,0 +1,261 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_vn "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/virtualnetwork"
+)
+
+func testVirtualNetwork() *goca_vn.VirtualNetwork {
+	return &goca_vn.VirtualNetwork{
+		ID:   3,
+		Name: "public",
+		ARs: []goca_vn.AR{
+			{
+				ID:   "0",
+				Type: "IP4",
+				IP:   "10.0.0.10",
+				Size: 3,
+				Leases: []goca_vn.Lease{
+					{IP: "10.0.0.10", VM: 7},
+					{IP: "10.0.0.11", VM: -1},
+				},
+			},
+			{
+				ID:   "1",
+				Type: "IP4",
+				IP:   "10.0.1.254",
+				Size: 4,
+			},
+			{
+				ID:   "2",
+				Type: "IP6_STATIC",
+				IP6:  "2001:db8::fffe",
+				Size: 4,
+				Leases: []goca_vn.Lease{
+					{IP6: "2001:db8::fffe", VM: 8},
+				},
+			},
+			{
+				ID:           "3",
+				Type:         "IP6",
+				GlobalPrefix: "2001:db8:1::",
+				Size:         256,
+			},
+		},
+	}
+}
+
+func TestInAddressRanges(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{address: "10.0.0.10", want: true},
+		{address: "10.0.0.12", want: true},
+		{address: "10.0.0.13", want: false},
+		{address: "10.0.0.9", want: false},
+		{address: "10.0.1.254", want: true},
+		{address: "10.0.2.1", want: true},
+		{address: "10.0.2.2", want: false},
+		{address: "192.168.0.1", want: false},
+		{address: "2001:db8::fffe", want: true},
+		{address: "2001:db8::1:1", want: true},
+		{address: "2001:db8::1:2", want: false},
+		{address: "2001:db8::fffd", want: false},
+		{address: "::ffff:10.0.0.10", want: false},
+		{address: "2001:db8:1::5", want: false},
+	}
+	vn := testVirtualNetwork()
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := inAddressRanges(vn, netip.MustParseAddr(tt.address)); got != tt.want {
+				t.Fatalf("inAddressRanges(%s) = %v, want %v", tt.address, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstFreeAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		ars     []goca_vn.AR
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "skips used and held addresses",
+			ars:  testVirtualNetwork().ARs[:1],
+			want: "10.0.0.12",
+		},
+		{
+			name: "falls through a fully used range",
+			ars: []goca_vn.AR{
+				{IP: "10.0.0.10", Size: 1, Leases: []goca_vn.Lease{{IP: "10.0.0.10", VM: 7}}},
+				{IP: "10.0.1.254", Size: 4},
+			},
+			want: "10.0.1.254",
+		},
+		{
+			name: "static IPv6 range",
+			ars:  testVirtualNetwork().ARs[2:3],
+			want: "2001:db8::ffff",
+		},
+		{
+			name:    "SLAAC range",
+			ars:     testVirtualNetwork().ARs[3:],
+			wantErr: true,
+		},
+		{
+			name: "no free address left",
+			ars: []goca_vn.AR{
+				{IP: "10.0.0.10", Size: 2, Leases: []goca_vn.Lease{{IP: "10.0.0.10", VM: 7}, {IP: "10.0.0.11", VM: -1}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := firstFreeAddress(&goca_vn.VirtualNetwork{Name: "public", ARs: tt.ars})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("firstFreeAddress() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("firstFreeAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeVirtualNetworkServer answers one.vn.info with vn and records the other calls.
+type fakeVirtualNetworkServer struct {
+	vn    *goca_vn.VirtualNetwork
+	calls []string
+}
+
+func (s *fakeVirtualNetworkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var call struct {
+		MethodName string   `xml:"methodName"`
+		Params     []string `xml:"params>param>value>string"`
+	}
+	body, _ := io.ReadAll(r.Body)
+	if err := xml.Unmarshal(body, &call); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Info calls return the resource, the others return its ID.
+	result := "<i4>3</i4>"
+	if call.MethodName == "one.vn.info" {
+		vnXML, _ := xml.Marshal(s.vn)
+		var escaped strings.Builder
+		xml.EscapeText(&escaped, vnXML)
+		result = "<string>" + escaped.String() + "</string>"
+	} else {
+		s.calls = append(s.calls, strings.Join(strings.Fields(call.MethodName+" "+strings.Join(call.Params[1:], " ")), " "))
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	io.WriteString(w, `<?xml version="1.0"?><methodResponse><params><param><value><array><data>`+
+		`<value><boolean>1</boolean></value><value>`+result+`</value><value><i4>0</i4></value>`+
+		`</data></array></value></param></params></methodResponse>`)
+}
+
+func TestLeaseHold(t *testing.T) {
+	tests := []struct {
+		name       string
+		ip         string
+		wantIP     string
+		wantHeld   bool
+		wantPicked bool
+		wantCalls  []string
+		wantErr    bool
+	}{
+		{
+			name:      "free address",
+			ip:        "10.0.0.12",
+			wantIP:    "10.0.0.12",
+			wantHeld:  true,
+			wantCalls: []string{`one.vn.hold LEASES=[ IP="10.0.0.12" ]`},
+		},
+		{
+			name:     "address already on hold",
+			ip:       "10.0.0.11",
+			wantIP:   "10.0.0.11",
+			wantHeld: true,
+		},
+		{
+			name:    "address used by a VM",
+			ip:      "10.0.0.10",
+			wantIP:  "10.0.0.10",
+			wantErr: true,
+		},
+		{
+			name:   "address outside the ranges",
+			ip:     "192.168.0.1",
+			wantIP: "192.168.0.1",
+		},
+		{
+			name:      "non canonical IPv6 address",
+			ip:        "2001:0db8:0000:0000:0000:0000:0000:ffff",
+			wantIP:    "2001:db8::ffff",
+			wantHeld:  true,
+			wantCalls: []string{`one.vn.hold LEASES=[ IP6="2001:db8::ffff" ]`},
+		},
+		{
+			name:       "picks the first free address",
+			wantIP:     "10.0.0.12",
+			wantHeld:   true,
+			wantPicked: true,
+			wantCalls:  []string{`one.vn.hold LEASES=[ IP="10.0.0.12" ]`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &fakeVirtualNetworkServer{vn: testVirtualNetwork()}
+			httpServer := httptest.NewServer(server)
+			defer httpServer.Close()
+
+			client := goca.NewClient(goca.NewConfig("oneadmin", "password", httpServer.URL), nil)
+			lease, err := NewLease(&Clients{RPC2: client}, WithLeaseNetworkID(3), WithLeaseIP(tt.ip))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = lease.Hold()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Hold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if lease.IP != tt.wantIP || lease.Held != tt.wantHeld || lease.Picked != tt.wantPicked {
+				t.Fatalf("Hold() = IP %q held %v picked %v, want IP %q held %v picked %v",
+					lease.IP, lease.Held, lease.Picked, tt.wantIP, tt.wantHeld, tt.wantPicked)
+			}
+			if strings.Join(server.calls, "\n") != strings.Join(tt.wantCalls, "\n") {
+				t.Fatalf("Hold() calls = %q, want %q", server.calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -22,10 +22,13 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
 
 	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_params "github.com/OpenNebula/one/src/oca/go/src/goca/parameters"
 	goca_vm "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/vm"
 	goca_vm_keys "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/vm/keys"
 )
@@ -36,6 +39,33 @@ type Machine struct {
 	Name     string
 	RouterID int
 	Address4 string
+	Address6 string
+	NICs     []MachineNIC
+	State    goca_vm.State
+	LCMState goca_vm.LCMState
+	Error    string
+	Started  time.Time
+
+	CPU            *float64
+	VCPU           *int
+	MemoryMB       *int
+	RootDiskSizeMB *int
+	Disks          []infrav1.ONEMachineDisk
+	ImageNames     map[string]string
+	Networks       []infrav1.ONEMachineNetwork
+	FailureDomain  *infrav1.ONEFailureDomain
+	VMGroupID      int
+	VMGroupRole    string
+	SecurityGroups []int
+
+	userTemplate *goca_vm.UserTemplate
+}
+
+type MachineNIC struct {
+	NetworkName string
+	NetworkID   int
+	Alias       bool
+	Addresses   []string
 }
 
 type MachineOption func(*Machine)
@@ -51,12 +81,74 @@ func WithMachineRouterID(routerID int) MachineOption {
 	}
 }
 
+func WithMachineCPU(cpu float64) MachineOption {
+	return func(m *Machine) {
+		m.CPU = &cpu
+	}
+}
+func WithMachineVCPU(vcpu int) MachineOption {
+	return func(m *Machine) {
+		m.VCPU = &vcpu
+	}
+}
+func WithMachineMemoryMB(memoryMB int) MachineOption {
+	return func(m *Machine) {
+		m.MemoryMB = &memoryMB
+	}
+}
+func WithMachineRootDiskSizeMB(sizeMB int) MachineOption {
+	return func(m *Machine) {
+		m.RootDiskSizeMB = &sizeMB
+	}
+}
+func WithMachineDisks(disks []infrav1.ONEMachineDisk) MachineOption {
+	return func(m *Machine) {
+		m.Disks = disks
+	}
+}
+
+// WithMachineImageNames maps names of cluster images onto the image versions
+// holding their current content, disks referencing them are pointed there.
+func WithMachineImageNames(imageNames map[string]string) MachineOption {
+	return func(m *Machine) {
+		m.ImageNames = imageNames
+	}
+}
+func WithMachineNetworks(networks []infrav1.ONEMachineNetwork) MachineOption {
+	return func(m *Machine) {
+		m.Networks = networks
+	}
+}
+func WithMachineFailureDomain(failureDomain *infrav1.ONEFailureDomain) MachineOption {
+	return func(m *Machine) {
+		m.FailureDomain = failureDomain
+	}
+}
+func WithMachineVMGroup(vmGroupID int, vmGroupRole string) MachineOption {
+	return func(m *Machine) {
+		m.VMGroupID = vmGroupID
+		m.VMGroupRole = vmGroupRole
+	}
+}
+func WithMachineSecurityGroups(securityGroupIDs []int) MachineOption {
+	return func(m *Machine) {
+		m.SecurityGroups = securityGroupIDs
+	}
+}
+
 func NewMachine(clients *Clients, options ...MachineOption) (*Machine, error) {
 	if clients == nil {
 		return nil, fmt.Errorf("clients reference is nil")
 	}
 
-	m := &Machine{ctrl: goca.NewController(clients.RPC2), ID: -1, RouterID: -1}
+	m := &Machine{
+		ctrl:      goca.NewController(clients.RPC2),
+		ID:        -1,
+		RouterID:  -1,
+		VMGroupID: -1,
+		State:     -1,
+		LCMState:  -1,
+	}
 	for _, option := range options {
 		option(m)
 	}
@@ -75,19 +167,66 @@ func (m *Machine) ByID(vmID int) error {
 	m.ID = vm.ID
 	m.Name = vm.Name
 
-	address4, err := vm.Template.GetStrFromVec("CONTEXT", "ETH0_IP")
-	if err != nil {
-		return fmt.Errorf("Failed to fetch VM: %w", err)
+	// NOTE: unknown states are reported as -1 and not treated as errors.
+	m.State, m.LCMState, _ = vm.State()
+	m.userTemplate = &vm.UserTemplate
+	m.Error, _ = vm.UserTemplate.GetStr("ERROR")
+	m.Started = time.Unix(int64(vm.STime), 0)
+
+	m.Address4, _ = vm.Template.GetStrFromVec("CONTEXT", "ETH0_IP")
+	m.Address6, _ = vm.Template.GetStrFromVec("CONTEXT", "ETH0_IP6")
+
+	m.NICs = []MachineNIC{}
+	for nicIndex, nicVec := range getNICs(&vm.Template) {
+		m.NICs = append(m.NICs, generateMachineNIC(nicVec, false))
+		if nicIndex == 0 && m.Address6 == "" {
+			// SLAAC addresses are not contextualized, they are derived from the MAC.
+			m.Address6, _ = nicVec.GetStr("IP6_GLOBAL")
+		}
+	}
+	for _, nicVec := range getVectors(&vm.Template, "NIC_ALIAS") {
+		m.NICs = append(m.NICs, generateMachineNIC(nicVec, true))
 	}
-	m.Address4 = address4
 
 	return nil
 }
 
+// Address returns the primary address of the VM, IPv4 is preferred in dual-stack setups.
+// NOTE: it is empty for VMs without NICs, e.g. when their template has none.
+func (m *Machine) Address() string {
+	if m.Address4 != "" {
+		return m.Address4
+	}
+	return m.Address6
+}
+
+func generateMachineNIC(nicVec *goca_dyn.Vector, alias bool) MachineNIC {
+	nic := MachineNIC{NetworkID: -1, Alias: alias}
+	nic.NetworkName, _ = nicVec.GetStr("NETWORK")
+	if networkID, err := nicVec.GetInt("NETWORK_ID"); err == nil {
+		nic.NetworkID = networkID
+	}
+	for _, key := range []string{"IP", "IP6", "IP6_GLOBAL", "IP6_ULA"} {
+		if address, err := nicVec.GetStr(key); err == nil && address != "" {
+			nic.Addresses = append(nic.Addresses, address)
+		}
+	}
+	return nic
+}
+
+func (m *Machine) ByProviderID(providerID string) error {
+	vmID, err := strconv.Atoi(strings.TrimPrefix(providerID, "one://"))
+	if err != nil || !strings.HasPrefix(providerID, "one://") {
+		return fmt.Errorf("Invalid provider ID: %s", providerID)
+	}
+
+	return m.ByID(vmID)
+}
+
 func (m *Machine) ByName(vmName string) error {
 	vmID, err := m.ctrl.VMs().ByName(vmName)
 	if err != nil {
-		return fmt.Errorf("Failed to fetch VM: %w", err)
+		return fmt.Errorf("Failed to fetch VM: %w", classifyError(err))
 	}
 
 	return m.ByID(vmID)
@@ -114,7 +253,107 @@ func (m *Machine) FromTemplate(
 		vmTemplate.Template.Add("NAME", m.Name)
 	}
 
-	if network != nil {
+	// Override capacity defined in the VM template (if requested).
+	if m.CPU != nil {
+		vmTemplate.Template.CPU(*m.CPU)
+	}
+	if m.VCPU != nil {
+		vmTemplate.Template.VCPU(*m.VCPU)
+	}
+	if m.MemoryMB != nil {
+		vmTemplate.Template.Memory(*m.MemoryMB)
+	}
+
+	if m.RootDiskSizeMB != nil {
+		// Resize DISK 0, leave others intact.
+		disks := getDisks(&vmTemplate.Template)
+		if len(disks) == 0 {
+			return fmt.Errorf("Cannot resize root disk: VM template %s defines no DISK", templateName)
+		}
+		diskVec := disks[0]
+		diskVec.Del("SIZE")
+		diskVec.AddPair("SIZE", strconv.Itoa(*m.RootDiskSizeMB))
+	}
+
+	// Append extra disks after the ones defined in the VM template.
+	diskIndex := len(getDisks(&vmTemplate.Template))
+	for _, disk := range m.Disks {
+		diskVec := ensureDisk(&vmTemplate.Template, diskIndex)
+		diskIndex++
+		if disk.ImageName != nil {
+			diskVec.AddPair("IMAGE", *disk.ImageName)
+		} else {
+			diskVec.AddPair("TYPE", "fs")
+			if disk.Format != nil {
+				diskVec.AddPair("FORMAT", *disk.Format)
+			} else {
+				diskVec.AddPair("FORMAT", "raw")
+			}
+		}
+		if disk.SizeMB != nil {
+			diskVec.AddPair("SIZE", strconv.Itoa(int(*disk.SizeMB)))
+		}
+		if disk.Target != nil {
+			diskVec.AddPair("TARGET", *disk.Target)
+		}
+		if disk.DevPrefix != nil {
+			diskVec.AddPair("DEV_PREFIX", *disk.DevPrefix)
+		}
+	}
+
+	for _, diskVec := range getDisks(&vmTemplate.Template) {
+		imageName, err := diskVec.GetStr("IMAGE")
+		if err != nil {
+			continue
+		}
+		if resolvedName, ok := m.ImageNames[imageName]; ok && resolvedName != imageName {
+			diskVec.Del("IMAGE")
+			diskVec.AddPair("IMAGE", resolvedName)
+		}
+	}
+
+	if len(m.Networks) > 0 {
+		// Overwrite NICs in the requested order, leave others intact.
+		for nicIndex, machineNetwork := range m.Networks {
+			nicVec := ensureNIC(&vmTemplate.Template, nicIndex)
+			nicVec.Del("NETWORK")
+			nicVec.Del("NETWORK_ID")
+			nicVec.Del("NETWORK_UNAME")
+			if machineNetwork.ID != nil {
+				nicVec.AddPair("NETWORK_ID", strconv.Itoa(int(*machineNetwork.ID)))
+			} else {
+				nicVec.AddPair("NETWORK", *machineNetwork.Name)
+			}
+			if machineNetwork.IP != nil {
+				nicVec.Del("IP")
+				nicVec.AddPair("IP", *machineNetwork.IP)
+			}
+			if machineNetwork.IP6 != nil {
+				nicVec.Del("IP6")
+				nicVec.AddPair("IP6", *machineNetwork.IP6)
+			}
+			if machineNetwork.Gateway != nil {
+				nicVec.Del("GATEWAY")
+				nicVec.AddPair("GATEWAY", *machineNetwork.Gateway)
+			}
+			if machineNetwork.Gateway6 != nil {
+				nicVec.Del("GATEWAY6")
+				nicVec.AddPair("GATEWAY6", *machineNetwork.Gateway6)
+			}
+			if machineNetwork.DNS != nil {
+				nicVec.Del("DNS")
+				nicVec.AddPair("DNS", *machineNetwork.DNS)
+			}
+			if len(machineNetwork.SecurityGroups) > 0 {
+				securityGroups := make([]string, 0, len(machineNetwork.SecurityGroups))
+				for _, securityGroupID := range machineNetwork.SecurityGroups {
+					securityGroups = append(securityGroups, strconv.Itoa(int(securityGroupID)))
+				}
+				nicVec.Del("SECURITY_GROUPS")
+				nicVec.AddPair("SECURITY_GROUPS", strings.Join(securityGroups, ","))
+			}
+		}
+	} else if network != nil {
 		// Overwrite NIC 0, leave others intact.
 		nicVec := ensureNIC(&vmTemplate.Template, 0)
 		nicVec.Del("NETWORK")
@@ -123,12 +362,60 @@ func (m *Machine) FromTemplate(
 			nicVec.Del("GATEWAY")
 			nicVec.AddPair("GATEWAY", *network.Gateway)
 		}
+		if network.Gateway6 != nil {
+			nicVec.Del("GATEWAY6")
+			nicVec.AddPair("GATEWAY6", *network.Gateway6)
+		}
 		if network.DNS != nil {
 			nicVec.Del("DNS")
 			nicVec.AddPair("DNS", *network.DNS)
 		}
 	}
 
+	if len(m.SecurityGroups) > 0 {
+		// Attach managed security groups to every NIC, keep the ones already defined.
+		for _, nicVec := range getNICs(&vmTemplate.Template) {
+			securityGroups := []string{}
+			if existing, err := nicVec.GetStr("SECURITY_GROUPS"); err == nil && existing != "" {
+				securityGroups = strings.Split(existing, ",")
+			}
+			for _, securityGroupID := range m.SecurityGroups {
+				if !slices.Contains(securityGroups, strconv.Itoa(securityGroupID)) {
+					securityGroups = append(securityGroups, strconv.Itoa(securityGroupID))
+				}
+			}
+			nicVec.Del("SECURITY_GROUPS")
+			nicVec.AddPair("SECURITY_GROUPS", strings.Join(securityGroups, ","))
+		}
+	}
+
+	if m.FailureDomain != nil {
+		// Restrict scheduling to the failure domain, keep requirements from the VM template.
+		schedRequirements := []string{fmt.Sprintf("CLUSTER_ID = %d", m.FailureDomain.ClusterID)}
+		if m.FailureDomain.HostRequirements != nil && *m.FailureDomain.HostRequirements != "" {
+			schedRequirements = append(schedRequirements, *m.FailureDomain.HostRequirements)
+		}
+		mergeSchedRequirements(&vmTemplate.Template, "SCHED_REQUIREMENTS", schedRequirements)
+
+		if m.FailureDomain.DatastoreRequirements != nil && *m.FailureDomain.DatastoreRequirements != "" {
+			mergeSchedRequirements(&vmTemplate.Template, "SCHED_DS_REQUIREMENTS",
+				[]string{*m.FailureDomain.DatastoreRequirements})
+		}
+	}
+
+	if m.VMGroupID >= 0 {
+		vmGroupVec, err := vmTemplate.Template.GetVector("VMGROUP")
+		if err != nil {
+			vmGroupVec = vmTemplate.Template.AddVector("VMGROUP")
+		}
+		vmGroupVec.Del("VMGROUP_ID")
+		vmGroupVec.Del("VMGROUP_NAME")
+		vmGroupVec.Del("VMGROUP_UNAME")
+		vmGroupVec.Del("ROLE")
+		vmGroupVec.AddPair("VMGROUP_ID", strconv.Itoa(m.VMGroupID))
+		vmGroupVec.AddPair("ROLE", m.VMGroupRole)
+	}
+
 	contextVec, err := vmTemplate.Template.GetVector("CONTEXT")
 	if err != nil {
 		return fmt.Errorf("Failed to get context vector: %w", err)
@@ -153,8 +440,11 @@ func (m *Machine) FromTemplate(
 	}
 
 	if router != nil {
+		if m.Address() == "" {
+			return fmt.Errorf("Failed to register VM %d in VR: no IPv4 or IPv6 address found", m.ID)
+		}
 		// Mark this machine as a Control-Plane backend in the VR (dynamic LB).
-		update := generateVMTemplateVRouterLBParams(router, m.RouterID, m.Address4)
+		update := generateVMTemplateVRouterLBParams(router, m.RouterID, m.Address())
 		if err := m.ctrl.VM(m.ID).Update(update.String(), 1); err != nil {
 			return fmt.Errorf("Failed to update VM: %w", err)
 		}
@@ -163,34 +453,87 @@ func (m *Machine) FromTemplate(
 	return nil
 }
 
-func generateVMTemplateVRouterLBParams(router *infrav1.ONEVirtualRouter, routerID int, serverAddress string) *goca_vm.Template {
-	update := goca_vm.NewTemplate()
-	if len(router.ListenerPorts) == 0 {
-		//defaults to kubernetes api port load balancing
-		if routerID >= 0 {
-			update.Add("ONEGATE_HAPROXY_LB0_ID", routerID)
+// UpdateRouterBackend refreshes the VR load balancer backend params of an
+// existing VM, e.g. after the VR listeners have been changed.
+func (m *Machine) UpdateRouterBackend(router *infrav1.ONEVirtualRouter) error {
+	if !m.Exists() || m.userTemplate == nil || m.Address() == "" {
+		return nil
+	}
+
+	desired := generateVMTemplateVRouterLBParams(router, m.RouterID, m.Address())
+
+	update := goca_dyn.NewTemplate()
+	changed := false
+	for _, element := range m.userTemplate.Elements {
+		if strings.HasPrefix(element.Key(), "ONEGATE_HAPROXY_LB") {
+			if pair, ok := element.(*goca_dyn.Pair); ok {
+				if value, err := desired.GetStr(pair.Key()); err == nil && value == pair.Value {
+					continue
+				}
+			}
+			changed = true
+			continue
+		}
+		update.Elements = append(update.Elements, element)
+	}
+	for _, element := range desired.Elements {
+		if pair, ok := element.(*goca_dyn.Pair); ok {
+			if value, err := m.userTemplate.GetStr(pair.Key()); err != nil || value != pair.Value {
+				changed = true
+			}
 		}
-		update.Add("ONEGATE_HAPROXY_LB0_IP", "<ETH0_EP0>")
-		update.Add("ONEGATE_HAPROXY_LB0_PORT", "6443")
-		update.Add("ONEGATE_HAPROXY_LB0_SERVER_HOST", serverAddress)
-		update.Add("ONEGATE_HAPROXY_LB0_SERVER_PORT", "6443")
-		return update
+		update.Elements = append(update.Elements, element)
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := m.ctrl.VM(m.ID).Update(update.String(), goca_params.Replace); err != nil {
+		return fmt.Errorf("Failed to update VM: %w", err)
+	}
+	return nil
+}
+
+func mergeSchedRequirements(vmTemplate *goca_vm.Template, key string, requirements []string) {
+	if existing, err := vmTemplate.GetStr(key); err == nil && existing != "" {
+		requirements = append([]string{existing}, requirements...)
+	}
+	for idx, requirement := range requirements {
+		requirements[idx] = fmt.Sprintf("(%s)", requirement)
 	}
+	vmTemplate.Del(key)
+	vmTemplate.Add(goca_vm_keys.Template(key), strings.Join(requirements, " & "))
+}
 
-	slices.Sort(router.ListenerPorts)
-	for idx, port := range router.ListenerPorts {
+func generateVMTemplateVRouterLBParams(router *infrav1.ONEVirtualRouter, routerID int, serverAddress string) *goca_vm.Template {
+	update := goca_vm.NewTemplate()
+	for idx, listener := range routerListeners(router) {
 		//NOTE: Pass ports as strings, as the template make pair method doesn't support int32 values
 		if routerID >= 0 {
 			update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_ID", idx)), routerID)
 		}
 		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_IP", idx)), "<ETH0_EP0>")
-		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_PORT", idx)), strconv.Itoa(int(port)))
+		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_PORT", idx)), strconv.Itoa(int(listener.Port)))
 		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_SERVER_HOST", idx)), serverAddress)
-		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_SERVER_PORT", idx)), strconv.Itoa(int(port)))
+		update.Add(goca_vm_keys.Template(fmt.Sprintf("ONEGATE_HAPROXY_LB%d_SERVER_PORT", idx)), strconv.Itoa(int(listenerTargetPort(listener))))
 	}
 	return update
 }
 
+// Terminate requests a graceful (ACPI) shutdown of the VM.
+func (m *Machine) Terminate() error {
+	if !m.Exists() {
+		return nil
+	}
+
+	if err := m.ctrl.VM(m.ID).Terminate(); err != nil {
+		return fmt.Errorf("Failed to terminate VM: %w", err)
+	}
+
+	return nil
+}
+
+// Delete terminates the VM immediately, without waiting for the guest to shut down.
 func (m *Machine) Delete() error {
 	if !m.Exists() {
 		return nil
@@ -200,10 +543,57 @@ func (m *Machine) Delete() error {
 		return fmt.Errorf("Failed to delete VM: %w", err)
 	}
 
-	m.ID = -1
 	return nil
 }
 
+func (m *Machine) Done() bool {
+	return m.Exists() && m.State == goca_vm.Done
+}
+
+func (m *Machine) StateString() (string, string) {
+	var state, lcmState string
+	if m.State >= 0 {
+		state = m.State.String()
+	}
+	if m.State == goca_vm.Active && m.LCMState >= 0 {
+		lcmState = m.LCMState.String()
+	}
+	return state, lcmState
+}
+
+func (m *Machine) Running() bool {
+	return m.Exists() && m.State == goca_vm.Active && m.LCMState == goca_vm.Running
+}
+
+// Failure returns a human readable description of the problem if the VM
+// is in a state it cannot recover from on its own, empty string otherwise.
+func (m *Machine) Failure() string {
+	if !m.Exists() {
+		return ""
+	}
+
+	state, lcmState := m.StateString()
+
+	var failure string
+	switch {
+	case m.State == goca_vm.Done:
+		failure = fmt.Sprintf("VM %d is in %s state", m.ID, state)
+	case m.State == goca_vm.CloningFailure:
+		failure = fmt.Sprintf("VM %d is in %s state", m.ID, state)
+	case m.State == goca_vm.Active && m.LCMState == goca_vm.Unknown:
+		failure = fmt.Sprintf("VM %d is in %s state", m.ID, lcmState)
+	case m.State == goca_vm.Active && strings.HasSuffix(lcmState, "_FAILURE"):
+		failure = fmt.Sprintf("VM %d is in %s state", m.ID, lcmState)
+	default:
+		return ""
+	}
+
+	if len(m.Error) > 0 {
+		failure = fmt.Sprintf("%s: %s", failure, m.Error)
+	}
+	return failure
+}
+
 func (m *Machine) NodeName() (string, error) {
 	if !m.Exists() {
 		return "", fmt.Errorf("Machine does not exist yet")
@@ -212,7 +602,7 @@ func (m *Machine) NodeName() (string, error) {
 	if len(m.Name) > 0 {
 		return m.Name, nil
 	} else {
-		nodeName := fmt.Sprintf("ip-%s", strings.Replace(m.Address4, ".", "-", -1))
+		nodeName := fmt.Sprintf("ip-%s", strings.NewReplacer(".", "-", ":", "-").Replace(m.Address()))
 		return nodeName, nil
 	}
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"testing"
+
+	goca_vm "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/vm"
+)
+
+func TestMachineFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int
+		state    goca_vm.State
+		lcmState goca_vm.LCMState
+		vmError  string
+		want     string
+	}{
+		{
+			name:     "missing",
+			id:       -1,
+			state:    -1,
+			lcmState: -1,
+		},
+		{
+			name:     "running",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.Running,
+		},
+		{
+			name:     "booting",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.Boot,
+		},
+		{
+			name:     "pending",
+			id:       7,
+			state:    goca_vm.Pending,
+			lcmState: goca_vm.LcmInit,
+		},
+		{
+			name:     "poweroff",
+			id:       7,
+			state:    goca_vm.Poweroff,
+			lcmState: goca_vm.LcmInit,
+		},
+		{
+			name:     "done",
+			id:       7,
+			state:    goca_vm.Done,
+			lcmState: goca_vm.LcmInit,
+			want:     "VM 7 is in DONE state",
+		},
+		{
+			name:     "cloning failure",
+			id:       7,
+			state:    goca_vm.CloningFailure,
+			lcmState: goca_vm.LcmInit,
+			want:     "VM 7 is in CLONINGFAILURE state",
+		},
+		{
+			name:     "unknown",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.Unknown,
+			want:     "VM 7 is in UNKNOWN state",
+		},
+		{
+			name:     "boot failure",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.BootFailure,
+			want:     "VM 7 is in BOOT_FAILURE state",
+		},
+		{
+			name:     "prolog failure with error",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.PrologFailure,
+			vmError:  "Error copying image",
+			want:     "VM 7 is in PROLOG_FAILURE state: Error copying image",
+		},
+		{
+			name:     "epilog failure",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.EpilogFailure,
+			want:     "VM 7 is in EPILOG_FAILURE state",
+		},
+		{
+			name:     "boot migrate failure",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.BootMigrateFailure,
+			want:     "VM 7 is in BOOT_MIGRATE_FAILURE state",
+		},
+		{
+			name:     "error on a running VM",
+			id:       7,
+			state:    goca_vm.Active,
+			lcmState: goca_vm.Running,
+			vmError:  "Error saving snapshot",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Machine{ID: tt.id, State: tt.state, LCMState: tt.lcmState, Error: tt.vmError}
+			if got := m.Failure(); got != tt.want {
+				t.Fatalf("Failure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
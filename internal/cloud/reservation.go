@@ -0,0 +1,118 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"strconv"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+)
+
+type Reservation struct {
+	ctrl            *goca.Controller
+	ID              int
+	Name            string
+	ParentNetworkID int
+	Ranges          []ReservationRange
+}
+
+type ReservationRange struct {
+	IP   string
+	Size int
+}
+
+type ReservationOption func(*Reservation)
+
+func WithReservationName(name string) ReservationOption {
+	return func(r *Reservation) {
+		r.Name = name
+	}
+}
+
+func NewReservation(clients *Clients, options ...ReservationOption) (*Reservation, error) {
+	if clients == nil {
+		return nil, fmt.Errorf("clients reference is nil")
+	}
+
+	r := &Reservation{ctrl: goca.NewController(clients.RPC2), ID: -1, ParentNetworkID: -1}
+	for _, option := range options {
+		option(r)
+	}
+	return r, nil
+}
+
+func (r *Reservation) Exists() bool {
+	return r.ID >= 0
+}
+
+func (r *Reservation) ByID(vnID int) error {
+	vn, err := r.ctrl.VirtualNetwork(vnID).Info(true)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch reservation: %w", err)
+	}
+	r.ID = vn.ID
+	r.Name = vn.Name
+
+	r.ParentNetworkID = -1
+	if parentNetworkID, err := strconv.Atoi(vn.ParentNetworkID); err == nil {
+		r.ParentNetworkID = parentNetworkID
+	}
+
+	r.Ranges = []ReservationRange{}
+	for _, ar := range vn.ARs {
+		r.Ranges = append(r.Ranges, ReservationRange{IP: ar.IP, Size: ar.Size})
+	}
+
+	return nil
+}
+
+func (r *Reservation) ByName(vnName string) error {
+	vnID, err := r.ctrl.VirtualNetworks().ByName(vnName)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch reservation: %w", classifyError(err))
+	}
+
+	return r.ByID(vnID)
+}
+
+// FromNetwork reserves a contiguous block of size addresses from the parent network.
+func (r *Reservation) FromNetwork(networkName string, size int) error {
+	if r.Exists() {
+		return nil
+	}
+
+	parentID, err := r.ctrl.VirtualNetworks().ByName(networkName)
+	if err != nil {
+		return fmt.Errorf("Failed to find parent network: %w", err)
+	}
+
+	reserveTemplate := goca_dyn.NewTemplate()
+	reserveTemplate.AddPair("NAME", r.Name)
+	reserveTemplate.AddPair("SIZE", strconv.Itoa(size))
+
+	vnID, err := r.ctrl.VirtualNetwork(parentID).Reserve(reserveTemplate.String())
+	if err != nil {
+		return fmt.Errorf("Failed to create reservation: %w", err)
+	}
+	if err := r.ByID(vnID); err != nil {
+		return fmt.Errorf("Failed to create reservation: %w", err)
+	}
+
+	return nil
+}
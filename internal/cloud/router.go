@@ -17,23 +17,47 @@ limitations under the License.
 package cloud
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"net"
+	"regexp"
 	"slices"
 	"strconv"
+	"strings"
 
 	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
 
 	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_params "github.com/OpenNebula/one/src/oca/go/src/goca/parameters"
 	goca_vr "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/virtualrouter"
+	goca_vm "github.com/OpenNebula/one/src/oca/go/src/goca/schemas/vm"
 )
 
 type Router struct {
-	ctrl        *goca.Controller
-	ID          int
-	Name        string
-	Replicas    int
-	FloatingIPs []string
+	ctrl     *goca.Controller
+	ID       int
+	Name     string
+	Replicas int
+	// FloatingIPs and FloatingIP6s only list the NICs that have one (so they are
+	// not indexed by NIC), use NICs to get the floating IPs of a given NIC.
+	FloatingIPs  []string
+	FloatingIP6s []string
+	NICs         []RouterNIC
+	VMIDs        []int
+	ContextHash  string
+	ContextKeys  []string
+}
+
+// vrouterListenerKey matches the context keys generated for the VR listeners.
+var vrouterListenerKey = regexp.MustCompile(`^ONEAPP_VNF_HAPROXY_LB[0-9]+_(IP|PORT)$`)
+
+type RouterNIC struct {
+	NetworkName string
+	NetworkID   int
+	FloatingIP  string
+	FloatingIP6 string
 }
 
 type RouterOption func(*Router)
@@ -72,11 +96,36 @@ func (r *Router) ByID(vrID int) error {
 	}
 	r.ID = vr.ID
 	r.Name = vr.Name
+	r.VMIDs = slices.Clone(vr.VMs.ID)
+	slices.Sort(r.VMIDs)
+	r.ContextHash, _ = vr.Template.GetStr("CAPONE_CONTEXT_HASH")
+	r.ContextKeys = []string{}
+	if contextKeys, err := vr.Template.GetStr("CAPONE_CONTEXT_KEYS"); err == nil && contextKeys != "" {
+		r.ContextKeys = strings.Split(contextKeys, ",")
+	}
 
+	r.FloatingIPs = []string{}
+	r.FloatingIP6s = []string{}
+	r.NICs = []RouterNIC{}
 	for _, nicVec := range getNICs(&vr.Template) {
+		nic := RouterNIC{NetworkID: -1}
+		nic.NetworkName, _ = nicVec.GetStr("NETWORK")
+		if networkID, err := nicVec.GetInt("NETWORK_ID"); err == nil {
+			nic.NetworkID = networkID
+		}
 		if vrIP, err := nicVec.GetStr("VROUTER_IP"); err == nil {
 			r.FloatingIPs = append(r.FloatingIPs, vrIP)
+			nic.FloatingIP = vrIP
+		}
+		for _, key := range []string{"VROUTER_IP6", "VROUTER_IP6_GLOBAL"} {
+			if vrIP6, err := nicVec.GetStr(key); err == nil && vrIP6 != "" {
+				r.FloatingIP6s = append(r.FloatingIP6s, vrIP6)
+				if nic.FloatingIP6 == "" {
+					nic.FloatingIP6 = vrIP6
+				}
+			}
 		}
+		r.NICs = append(r.NICs, nic)
 	}
 
 	return nil
@@ -85,7 +134,7 @@ func (r *Router) ByID(vrID int) error {
 func (r *Router) ByName(vrName string) error {
 	vrID, err := r.ctrl.VirtualRouterByName(vrName)
 	if err != nil {
-		return fmt.Errorf("Failed to fetch VR: %w", err)
+		return fmt.Errorf("Failed to fetch VR: %w", classifyError(err))
 	}
 
 	return r.ByID(vrID)
@@ -99,14 +148,10 @@ func (r *Router) FromTemplate(
 		return nil
 	}
 
-	vmTemplateID, err := r.ctrl.Templates().ByName(virtualRouter.TemplateName)
-	if err != nil {
+	// Fail early, before creating the VR itself.
+	if _, err := r.ctrl.Templates().ByName(virtualRouter.TemplateName); err != nil {
 		return fmt.Errorf("Failed to find VR template: %w", err)
 	}
-	vmTemplate, err := r.ctrl.Template(vmTemplateID).Info(false, true)
-	if err != nil {
-		return fmt.Errorf("Failed to fetch VR template: %w", err)
-	}
 
 	vrTemplate := goca_vr.NewTemplate()
 	vrTemplate.Add("NAME", r.Name)
@@ -124,9 +169,7 @@ func (r *Router) FromTemplate(
 		} else {
 			nicVec.AddPair("FLOATING_ONLY", "NO")
 		}
-		if publicNetwork.FloatingIP != nil && net.ParseIP(*publicNetwork.FloatingIP) != nil {
-			nicVec.AddPair("IP", *publicNetwork.FloatingIP)
-		}
+		addFloatingIPs(nicVec, publicNetwork)
 	}
 	if privateNetwork != nil {
 		nicIndex++
@@ -138,9 +181,7 @@ func (r *Router) FromTemplate(
 		} else {
 			nicVec.AddPair("FLOATING_ONLY", "YES")
 		}
-		if privateNetwork.FloatingIP != nil && net.ParseIP(*privateNetwork.FloatingIP) != nil {
-			nicVec.AddPair("IP", *privateNetwork.FloatingIP)
-		}
+		addFloatingIPs(nicVec, privateNetwork)
 	}
 
 	vrID, err := r.ctrl.VirtualRouters().Create(vrTemplate.String())
@@ -151,29 +192,132 @@ func (r *Router) FromTemplate(
 		return fmt.Errorf("Failed to create VR: %w", err)
 	}
 
-	contextVec, err := vmTemplate.Template.GetVector("CONTEXT")
+	return r.instantiate(virtualRouter, r.Replicas)
+}
+
+func addFloatingIPs(nicVec *goca_dyn.Vector, network *infrav1.ONEVirtualNetwork) {
+	for _, floatingIP := range []*string{network.FloatingIP, network.FloatingIP6} {
+		if floatingIP == nil {
+			continue
+		}
+		ip := net.ParseIP(*floatingIP)
+		switch {
+		case ip == nil:
+		case ip.To4() != nil:
+			nicVec.AddPair("IP", *floatingIP)
+		default:
+			nicVec.AddPair("IP6", *floatingIP)
+		}
+	}
+}
+
+// Sync brings an existing VR in line with the spec, it scales VR instances
+// up or down and updates the context of running instances when it drifts.
+func (r *Router) Sync(virtualRouter *infrav1.ONEVirtualRouter) error {
+	if !r.Exists() {
+		return nil
+	}
+
+	vms, err := r.activeVMs()
 	if err != nil {
-		return fmt.Errorf("Failed to get context vector: %w", err)
+		return err
 	}
-	contextMap := map[string]string{}
-	contextMap["ONEAPP_VNF_HAPROXY_ENABLED"] = "YES"
-	contextMap["ONEAPP_VNF_HAPROXY_ONEGATE_ENABLED"] = "YES"
-	if len(virtualRouter.ListenerPorts) == 0 {
-		//defaults to kubernets api port load balancing
-		contextMap["ONEAPP_VNF_HAPROXY_LB0_IP"] = "<ETH0_EP0>"
-		contextMap["ONEAPP_VNF_HAPROXY_LB0_PORT"] = "6443"
+	if len(vms) > r.Replicas {
+		// Remove the most recently created instances first.
+		for _, vm := range vms[r.Replicas:] {
+			if err := r.ctrl.VM(vm.ID).TerminateHard(); err != nil {
+				return fmt.Errorf("Failed to scale down VR: %w", err)
+			}
+		}
+		vms = vms[:r.Replicas]
 	}
-	slices.Sort(virtualRouter.ListenerPorts)
-	for idx, port := range virtualRouter.ListenerPorts {
-		contextMap[fmt.Sprintf("ONEAPP_VNF_HAPROXY_LB%d_IP", idx)] = "<ETH0_EP0>"
-		contextMap[fmt.Sprintf("ONEAPP_VNF_HAPROXY_LB%d_PORT", idx)] = strconv.Itoa(int(port))
+	r.VMIDs = make([]int, 0, len(vms))
+	for _, vm := range vms {
+		r.VMIDs = append(r.VMIDs, vm.ID)
 	}
-	updateContext(contextVec, &contextMap)
-	if virtualRouter.ExtraContext != nil {
-		updateContext(contextVec, &virtualRouter.ExtraContext)
+
+	// NOTE: Update the context before scaling up, new instances get the current context anyway.
+	contextMap := generateVRouterContext(virtualRouter)
+	contextHash := hashContext(contextMap)
+	if contextHash != r.ContextHash {
+		for _, vm := range vms {
+			contextVec, err := vm.Template.GetVector("CONTEXT")
+			if err != nil {
+				return fmt.Errorf("Failed to get context vector: %w", err)
+			}
+			removeStaleContext(contextVec, r.ContextKeys, contextMap)
+			updateContext(contextVec, &contextMap)
+			if err := r.ctrl.VM(vm.ID).UpdateConf(contextVec.String()); err != nil {
+				return fmt.Errorf("Failed to update VR instance context: %w", err)
+			}
+		}
+		if err := r.setContextState(contextMap); err != nil {
+			return err
+		}
+	}
+
+	if len(vms) < r.Replicas {
+		return r.instantiate(virtualRouter, r.Replicas-len(vms))
+	}
+	return nil
+}
+
+// activeVMs returns the VR instances (ordered by ID) which are not being terminated.
+func (r *Router) activeVMs() ([]*goca_vm.VM, error) {
+	vms := []*goca_vm.VM{}
+	for _, vmID := range r.VMIDs {
+		vm, err := r.ctrl.VM(vmID).Info(true)
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Failed to fetch VR instance: %w", err)
+		}
+		state, lcmState, _ := vm.State()
+		if state == goca_vm.Done || slices.Contains([]goca_vm.LCMState{
+			goca_vm.Shutdown, goca_vm.Epilog, goca_vm.CleanupDelete,
+		}, lcmState) {
+			continue
+		}
+		vms = append(vms, vm)
 	}
+	return vms, nil
+}
+
+// removeStaleContext deletes the context keys applied before (and the generated
+// listener keys) which are not part of the desired context anymore.
+func removeStaleContext(contextVec *goca_dyn.Vector, appliedKeys []string, contextMap map[string]string) {
+	staleKeys := slices.Clone(appliedKeys)
+	for _, pair := range contextVec.Pairs {
+		if vrouterListenerKey.MatchString(pair.Key()) {
+			staleKeys = append(staleKeys, pair.Key())
+		}
+	}
+	for _, key := range staleKeys {
+		if _, ok := contextMap[key]; !ok {
+			contextVec.Del(key)
+		}
+	}
+}
+
+func (r *Router) instantiate(virtualRouter *infrav1.ONEVirtualRouter, count int) error {
+	vmTemplateID, err := r.ctrl.Templates().ByName(virtualRouter.TemplateName)
+	if err != nil {
+		return fmt.Errorf("Failed to find VR template: %w", err)
+	}
+	vmTemplate, err := r.ctrl.Template(vmTemplateID).Info(false, true)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch VR template: %w", err)
+	}
+
+	contextVec, err := vmTemplate.Template.GetVector("CONTEXT")
+	if err != nil {
+		return fmt.Errorf("Failed to get context vector: %w", err)
+	}
+	contextMap := generateVRouterContext(virtualRouter)
+	updateContext(contextVec, &contextMap)
 	if _, err := r.ctrl.VirtualRouter(r.ID).Instantiate(
-		r.Replicas,
+		count,
 		vmTemplateID,
 		"",    // name
 		false, // hold
@@ -182,9 +326,84 @@ func (r *Router) FromTemplate(
 		return fmt.Errorf("Failed to create VR: %w", err)
 	}
 
+	return r.setContextState(contextMap)
+}
+
+// setContextState records the hash and the keys of the context applied to the VR
+// instances, so drift is detected and removed keys are cleaned up later on.
+func (r *Router) setContextState(contextMap map[string]string) error {
+	contextHash := hashContext(contextMap)
+	contextKeys := make([]string, 0, len(contextMap))
+	for k := range contextMap {
+		contextKeys = append(contextKeys, k)
+	}
+	slices.Sort(contextKeys)
+
+	update := goca_dyn.NewTemplate()
+	update.AddPair("CAPONE_CONTEXT_HASH", contextHash)
+	update.AddPair("CAPONE_CONTEXT_KEYS", strings.Join(contextKeys, ","))
+	if err := r.ctrl.VirtualRouter(r.ID).Update(update.String(), goca_params.Merge); err != nil {
+		return fmt.Errorf("Failed to update VR: %w", err)
+	}
+	r.ContextHash = contextHash
+	r.ContextKeys = contextKeys
 	return nil
 }
 
+func generateVRouterContext(virtualRouter *infrav1.ONEVirtualRouter) map[string]string {
+	contextMap := map[string]string{}
+	contextMap["ONEAPP_VNF_HAPROXY_ENABLED"] = "YES"
+	contextMap["ONEAPP_VNF_HAPROXY_ONEGATE_ENABLED"] = "YES"
+	for idx, listener := range routerListeners(virtualRouter) {
+		contextMap[fmt.Sprintf("ONEAPP_VNF_HAPROXY_LB%d_IP", idx)] = "<ETH0_EP0>"
+		contextMap[fmt.Sprintf("ONEAPP_VNF_HAPROXY_LB%d_PORT", idx)] = strconv.Itoa(int(listener.Port))
+	}
+	for k, v := range virtualRouter.ExtraContext {
+		contextMap[k] = v
+	}
+	return contextMap
+}
+
+// routerListeners returns the VR listeners ordered by frontend port, falling back
+// to the deprecated ListenerPorts and then to the kubernetes api port.
+func routerListeners(virtualRouter *infrav1.ONEVirtualRouter) []infrav1.ONEVirtualRouterListener {
+	var listeners []infrav1.ONEVirtualRouterListener
+	switch {
+	case len(virtualRouter.Listeners) > 0:
+		listeners = slices.Clone(virtualRouter.Listeners)
+	case len(virtualRouter.ListenerPorts) > 0:
+		for _, port := range virtualRouter.ListenerPorts {
+			listeners = append(listeners, infrav1.ONEVirtualRouterListener{Port: port})
+		}
+	default:
+		listeners = []infrav1.ONEVirtualRouterListener{{Port: 6443}}
+	}
+	slices.SortFunc(listeners, func(a, b infrav1.ONEVirtualRouterListener) int {
+		return int(a.Port) - int(b.Port)
+	})
+	return listeners
+}
+
+func listenerTargetPort(listener infrav1.ONEVirtualRouterListener) int32 {
+	if listener.TargetPort != nil {
+		return *listener.TargetPort
+	}
+	return listener.Port
+}
+
+func hashContext(contextMap map[string]string) string {
+	keys := make([]string, 0, len(contextMap))
+	for k := range contextMap {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	hash := sha256.New()
+	for _, k := range keys {
+		fmt.Fprintf(hash, "%s=%s\n", k, contextMap[k])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
 func (r *Router) Delete() error {
 	if !r.Exists() {
 		return nil
@@ -0,0 +1,212 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strconv"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_params "github.com/OpenNebula/one/src/oca/go/src/goca/parameters"
+)
+
+const (
+	SecurityGroupRoleControlPlane = "control-plane"
+	SecurityGroupRoleWorker       = "worker"
+)
+
+type SecurityGroup struct {
+	ctrl        *goca.Controller
+	ID          int
+	Name        string
+	RulesHash   string
+	NetworkName string
+}
+
+type SecurityGroupOption func(*SecurityGroup)
+
+func WithSecurityGroupName(name string) SecurityGroupOption {
+	return func(g *SecurityGroup) {
+		g.Name = name
+	}
+}
+
+// WithSecurityGroupNetworkName sets the node network, the only source allowed
+// to reach the etcd, kubelet and CNI ports.
+func WithSecurityGroupNetworkName(name string) SecurityGroupOption {
+	return func(g *SecurityGroup) {
+		g.NetworkName = name
+	}
+}
+
+func NewSecurityGroup(clients *Clients, options ...SecurityGroupOption) (*SecurityGroup, error) {
+	if clients == nil {
+		return nil, fmt.Errorf("clients reference is nil")
+	}
+
+	g := &SecurityGroup{ctrl: goca.NewController(clients.RPC2), ID: -1}
+	for _, option := range options {
+		option(g)
+	}
+	return g, nil
+}
+
+func (g *SecurityGroup) Exists() bool {
+	return g.ID >= 0
+}
+
+func (g *SecurityGroup) ByID(securityGroupID int) error {
+	securityGroup, err := g.ctrl.SecurityGroup(securityGroupID).Info(false)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch security group: %w", err)
+	}
+	g.ID = securityGroup.ID
+	g.Name = securityGroup.Name
+	g.RulesHash, _ = securityGroup.Template.GetStr("CAPONE_RULES_HASH")
+
+	return nil
+}
+
+func (g *SecurityGroup) ByName(securityGroupName string) error {
+	securityGroupID, err := g.ctrl.SecurityGroups().ByName(securityGroupName)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch security group: %w", classifyError(err))
+	}
+
+	return g.ByID(securityGroupID)
+}
+
+// FromSpec creates the security group for the given role, or updates its
+// rules (and propagates them to VMs) when the spec has changed.
+func (g *SecurityGroup) FromSpec(securityGroups *infrav1.ONESecurityGroups, role string) error {
+	if g.NetworkName == "" {
+		return fmt.Errorf("Failed to generate security group rules: node network is not set")
+	}
+	networkID, err := g.ctrl.VirtualNetworks().ByName(g.NetworkName)
+	if err != nil {
+		return fmt.Errorf("Failed to find node network: %w", err)
+	}
+
+	securityGroupTemplate := generateSecurityGroupTemplate(securityGroups, role, networkID)
+	rulesHash := securityGroupRulesHash(securityGroupTemplate)
+	securityGroupTemplate.AddPair("CAPONE_RULES_HASH", rulesHash)
+
+	if g.Exists() {
+		if g.RulesHash == rulesHash {
+			return nil
+		}
+		if err := g.ctrl.SecurityGroup(g.ID).Update(securityGroupTemplate.String(), goca_params.Replace); err != nil {
+			return fmt.Errorf("Failed to update security group: %w", err)
+		}
+		if err := g.ctrl.SecurityGroup(g.ID).Commit(false); err != nil {
+			return fmt.Errorf("Failed to commit security group: %w", err)
+		}
+		return g.ByID(g.ID)
+	}
+
+	securityGroupTemplate.AddPair("NAME", g.Name)
+	securityGroupID, err := g.ctrl.SecurityGroups().Create(securityGroupTemplate.String())
+	if err != nil {
+		return fmt.Errorf("Failed to create security group: %w", err)
+	}
+	if err := g.ByID(securityGroupID); err != nil {
+		return fmt.Errorf("Failed to create security group: %w", err)
+	}
+
+	return nil
+}
+
+// generateSecurityGroupTemplate only lets nodeNetworkID reach the etcd, kubelet and CNI ports.
+func generateSecurityGroupTemplate(securityGroups *infrav1.ONESecurityGroups, role string, nodeNetworkID int) *goca_dyn.Template {
+	securityGroupTemplate := goca_dyn.NewTemplate()
+
+	addRule := func(protocol, ruleType string) *goca_dyn.Vector {
+		ruleVec := &goca_dyn.Vector{XMLName: xml.Name{Local: "RULE"}}
+		ruleVec.AddPair("PROTOCOL", protocol)
+		ruleVec.AddPair("RULE_TYPE", ruleType)
+		securityGroupTemplate.Elements = append(securityGroupTemplate.Elements, ruleVec)
+		return ruleVec
+	}
+	// NOTE: An empty RANGE opens every port, so rules without one are skipped.
+	addPortRule := func(protocol, portRange string, networkID int) {
+		if portRange == "" {
+			return
+		}
+		ruleVec := addRule(protocol, "inbound")
+		ruleVec.AddPair("RANGE", portRange)
+		if networkID >= 0 {
+			ruleVec.AddPair("NETWORK_ID", networkID)
+		}
+	}
+
+	// Allow all outbound traffic and ICMP (ping, path MTU discovery).
+	addRule("ALL", "outbound")
+	addRule("ICMP", "inbound")
+	addRule("ICMPv6", "inbound")
+
+	if role == SecurityGroupRoleControlPlane {
+		addPortRule("TCP", strconv.Itoa(int(securityGroups.APIServerPort)), -1)
+		addPortRule("TCP", securityGroups.EtcdPortRange, nodeNetworkID)
+	}
+	addPortRule("TCP", strconv.Itoa(int(securityGroups.KubeletPort)), nodeNetworkID)
+	addPortRule("TCP", securityGroups.NodePortRange, -1)
+	addPortRule("UDP", securityGroups.NodePortRange, -1)
+
+	// Sort user rules, so reordering them in the spec does not change the rules hash.
+	for _, rule := range sortedSecurityGroupRules(securityGroups.CNIRules) {
+		addPortRule(string(rule.Protocol), rule.Range, nodeNetworkID)
+	}
+	for _, rule := range sortedSecurityGroupRules(securityGroups.ExtraRules) {
+		addPortRule(string(rule.Protocol), rule.Range, -1)
+	}
+
+	return securityGroupTemplate
+}
+
+func sortedSecurityGroupRules(rules []infrav1.ONESecurityGroupRule) []infrav1.ONESecurityGroupRule {
+	sorted := slices.Clone(rules)
+	slices.SortFunc(sorted, func(a, b infrav1.ONESecurityGroupRule) int {
+		return cmp.Or(cmp.Compare(a.Protocol, b.Protocol), cmp.Compare(a.Range, b.Range))
+	})
+	return sorted
+}
+
+func securityGroupRulesHash(securityGroupTemplate *goca_dyn.Template) string {
+	rulesHash := sha256.Sum256([]byte(securityGroupTemplate.String()))
+	return hex.EncodeToString(rulesHash[:])
+}
+
+func (g *SecurityGroup) Delete() error {
+	if !g.Exists() {
+		return nil
+	}
+
+	if err := g.ctrl.SecurityGroup(g.ID).Delete(); err != nil {
+		return fmt.Errorf("Failed to delete security group: %w", err)
+	}
+
+	g.ID = -1
+	return nil
+}
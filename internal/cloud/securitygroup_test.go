@@ -0,0 +1,175 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+)
+
+// securityGroupRules renders each RULE vector as "KEY=value" pairs, in order.
+func securityGroupRules(securityGroupTemplate *goca_dyn.Template) []string {
+	rules := []string{}
+	for _, element := range securityGroupTemplate.Elements {
+		ruleVec, ok := element.(*goca_dyn.Vector)
+		if !ok || ruleVec.Key() != "RULE" {
+			continue
+		}
+		pairs := []string{}
+		for _, pair := range ruleVec.Pairs {
+			pairs = append(pairs, pair.Key()+"="+pair.Value)
+		}
+		rules = append(rules, strings.Join(pairs, " "))
+	}
+	return rules
+}
+
+func TestGenerateSecurityGroupTemplate(t *testing.T) {
+	defaults := infrav1.ONESecurityGroups{
+		APIServerPort: 6443,
+		KubeletPort:   10250,
+		EtcdPortRange: "2379:2380",
+		NodePortRange: "30000:32767",
+		CNIRules:      []infrav1.ONESecurityGroupRule{{Protocol: "UDP", Range: "8472"}},
+	}
+	common := []string{
+		"PROTOCOL=ALL RULE_TYPE=outbound",
+		"PROTOCOL=ICMP RULE_TYPE=inbound",
+		"PROTOCOL=ICMPv6 RULE_TYPE=inbound",
+	}
+
+	tests := []struct {
+		name           string
+		securityGroups infrav1.ONESecurityGroups
+		role           string
+		want           []string
+	}{
+		{
+			name:           "control plane",
+			securityGroups: defaults,
+			role:           SecurityGroupRoleControlPlane,
+			want: append(slices.Clone(common),
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=6443",
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=2379:2380 NETWORK_ID=3",
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=10250 NETWORK_ID=3",
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=30000:32767",
+				"PROTOCOL=UDP RULE_TYPE=inbound RANGE=30000:32767",
+				"PROTOCOL=UDP RULE_TYPE=inbound RANGE=8472 NETWORK_ID=3",
+			),
+		},
+		{
+			name:           "worker",
+			securityGroups: defaults,
+			role:           SecurityGroupRoleWorker,
+			want: append(slices.Clone(common),
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=10250 NETWORK_ID=3",
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=30000:32767",
+				"PROTOCOL=UDP RULE_TYPE=inbound RANGE=30000:32767",
+				"PROTOCOL=UDP RULE_TYPE=inbound RANGE=8472 NETWORK_ID=3",
+			),
+		},
+		{
+			name: "empty ranges are skipped",
+			securityGroups: infrav1.ONESecurityGroups{
+				KubeletPort: 10250,
+				CNIRules:    []infrav1.ONESecurityGroupRule{{Protocol: "TCP", Range: ""}},
+				ExtraRules:  []infrav1.ONESecurityGroupRule{{Protocol: "UDP", Range: ""}},
+			},
+			role: SecurityGroupRoleWorker,
+			want: append(slices.Clone(common),
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=10250 NETWORK_ID=3",
+			),
+		},
+		{
+			name: "extra rules are open to any source",
+			securityGroups: infrav1.ONESecurityGroups{
+				KubeletPort: 10250,
+				ExtraRules: []infrav1.ONESecurityGroupRule{
+					{Protocol: "UDP", Range: "53"},
+					{Protocol: "TCP", Range: "80,443"},
+				},
+			},
+			role: SecurityGroupRoleWorker,
+			want: append(slices.Clone(common),
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=10250 NETWORK_ID=3",
+				"PROTOCOL=TCP RULE_TYPE=inbound RANGE=80,443",
+				"PROTOCOL=UDP RULE_TYPE=inbound RANGE=53",
+			),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := securityGroupRules(generateSecurityGroupTemplate(&tt.securityGroups, tt.role, 3))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("generateSecurityGroupTemplate() rules =\n%s\nwant\n%s",
+					strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestSecurityGroupRulesHash(t *testing.T) {
+	securityGroups := infrav1.ONESecurityGroups{
+		APIServerPort: 6443,
+		KubeletPort:   10250,
+		CNIRules: []infrav1.ONESecurityGroupRule{
+			{Protocol: "UDP", Range: "8472"},
+			{Protocol: "TCP", Range: "179"},
+		},
+		ExtraRules: []infrav1.ONESecurityGroupRule{
+			{Protocol: "TCP", Range: "443"},
+			{Protocol: "TCP", Range: "80"},
+		},
+	}
+	reordered := securityGroups
+	reordered.CNIRules = []infrav1.ONESecurityGroupRule{securityGroups.CNIRules[1], securityGroups.CNIRules[0]}
+	reordered.ExtraRules = []infrav1.ONESecurityGroupRule{securityGroups.ExtraRules[1], securityGroups.ExtraRules[0]}
+	changed := securityGroups
+	changed.ExtraRules = []infrav1.ONESecurityGroupRule{{Protocol: "TCP", Range: "8443"}}
+
+	hash := func(securityGroups infrav1.ONESecurityGroups, role string, networkID int) string {
+		return securityGroupRulesHash(generateSecurityGroupTemplate(&securityGroups, role, networkID))
+	}
+	base := hash(securityGroups, SecurityGroupRoleControlPlane, 3)
+
+	if got := hash(securityGroups, SecurityGroupRoleControlPlane, 3); got != base {
+		t.Errorf("hash is not deterministic: %s != %s", got, base)
+	}
+	if got := hash(reordered, SecurityGroupRoleControlPlane, 3); got != base {
+		t.Errorf("hash changed when rules were reordered: %s != %s", got, base)
+	}
+	if got := hash(changed, SecurityGroupRoleControlPlane, 3); got == base {
+		t.Errorf("hash did not change when rules were changed")
+	}
+	if got := hash(securityGroups, SecurityGroupRoleWorker, 3); got == base {
+		t.Errorf("hash did not change with the role")
+	}
+	if got := hash(securityGroups, SecurityGroupRoleControlPlane, 4); got == base {
+		t.Errorf("hash did not change with the node network")
+	}
+	if !reflect.DeepEqual(securityGroups.CNIRules, []infrav1.ONESecurityGroupRule{
+		{Protocol: "UDP", Range: "8472"}, {Protocol: "TCP", Range: "179"},
+	}) {
+		t.Errorf("spec rules were reordered in place: %v", securityGroups.CNIRules)
+	}
+}
@@ -18,8 +18,12 @@ package cloud
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+	goca_params "github.com/OpenNebula/one/src/oca/go/src/goca/parameters"
 )
 
 type Templates struct {
@@ -35,39 +39,146 @@ func NewTemplates(clients *Clients, clusterUID string) (*Templates, error) {
 	return &Templates{ctrl: goca.NewController(clients.RPC2), clusterUID: clusterUID}, nil
 }
 
-func (t *Templates) CreateTemplate(templateName, templateContent string) error {
-	templateClusterUID := fmt.Sprintf("%s-%s", templateName, t.clusterUID)
+// ResolveName returns the name of the VM template in OpenNebula, owned templates
+// are suffixed with the cluster UID, shared ones keep the requested name.
+func (t *Templates) ResolveName(templateName string, shared bool) string {
+	if shared {
+		return templateName
+	}
+	return fmt.Sprintf("%s-%s", templateName, t.clusterUID)
+}
 
-	existingID, err := t.ctrl.Templates().ByName(templateName)
-	if err != nil && err.Error() != "resource not found" {
-		return err
+// CreateTemplate creates the VM template (if missing) and returns its resolved name.
+// Shared templates created by the provider are marked with CAPONE_TEMPLATE_NAME and
+// record the UIDs of all the clusters using them in CAPONE_OWNERS, any other
+// template found under the shared name is used as is.
+func (t *Templates) CreateTemplate(templateName, templateContent string, shared bool) (string, error) {
+	resolvedName := t.ResolveName(templateName, shared)
+
+	// Owned templates created by earlier releases kept the plain name.
+	if !shared {
+		if err := t.deleteLegacyTemplate(templateName); err != nil {
+			return "", err
+		}
+	}
+
+	existingID, err := t.ctrl.Templates().ByName(resolvedName)
+	if err != nil && !isNotFound(err) {
+		return "", err
 	}
 
-	createNew := existingID < 0
+	if existingID < 0 {
+		templateSpec := fmt.Sprintf("NAME = \"%s\"\n", resolvedName)
+		if shared {
+			templateSpec += fmt.Sprintf("CAPONE_TEMPLATE_NAME = \"%s\"\n", templateName)
+			templateSpec += fmt.Sprintf("CAPONE_OWNERS = \"%s\"\n", t.clusterUID)
+		} else {
+			templateSpec += fmt.Sprintf("CLUSTER_UID = \"%s\"\n", t.clusterUID)
+		}
+		if _, err = t.ctrl.Templates().Create(templateSpec + templateContent); err != nil {
+			return "", fmt.Errorf("Failed to create VM template: %w", err)
+		}
+		return resolvedName, nil
+	}
 
-	if !createNew {
-		vmTemplate, err := t.ctrl.Template(existingID).Info(false, true)
+	if shared {
+		owners, managed, err := t.templateOwners(existingID)
 		if err != nil {
-			return fmt.Errorf("Failed to obtain existing VM template: %w", err)
+			return "", err
 		}
-
-		existingClusterUID, err := vmTemplate.Template.Get("CLUSTER_UID")
-		if err != nil || existingClusterUID != templateClusterUID {
-			if err = t.ctrl.Template(existingID).Delete(); err != nil {
-				return fmt.Errorf("Failed to delete existing VM template: %w", err)
+		if managed && !slices.Contains(owners, t.clusterUID) {
+			if err := t.setTemplateOwners(existingID, append(owners, t.clusterUID)); err != nil {
+				return "", err
 			}
-			createNew = true
 		}
 	}
 
-	if createNew {
-		templateSpec := fmt.Sprintf(
-			"NAME = \"%s\"\nCLUSTER_UID = \"%s\"\n%s",
-			templateName, templateClusterUID, templateContent)
-		if _, err = t.ctrl.Templates().Create(templateSpec); err != nil {
-			return fmt.Errorf("Failed to create VM template: %w", err)
+	return resolvedName, nil
+}
+
+// DeleteTemplate deletes an owned VM template, shared ones are only deleted
+// once no other cluster references them and never when they lack the provider marker.
+func (t *Templates) DeleteTemplate(templateName string, shared bool) error {
+	if !shared {
+		if err := t.deleteLegacyTemplate(templateName); err != nil {
+			return err
+		}
+	}
+
+	existingID, err := t.ctrl.Templates().ByName(t.ResolveName(templateName, shared))
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if existingID < 0 {
+		return nil
+	}
+
+	if shared {
+		owners, managed, err := t.templateOwners(existingID)
+		if err != nil {
+			return err
+		}
+		if !managed {
+			return nil
+		}
+		if idx := slices.Index(owners, t.clusterUID); idx >= 0 {
+			owners = slices.Delete(owners, idx, idx+1)
 		}
+		if len(owners) > 0 {
+			return t.setTemplateOwners(existingID, owners)
+		}
+	}
+
+	if err := t.ctrl.Template(existingID).Delete(); err != nil {
+		return fmt.Errorf("Failed to delete VM template: %w", err)
+	}
+	return nil
+}
+
+// deleteLegacyTemplate deletes the owned template created under the plain name
+// by earlier releases, which stamped it with CLUSTER_UID = "<name>-<cluster UID>".
+func (t *Templates) deleteLegacyTemplate(templateName string) error {
+	existingID, err := t.ctrl.Templates().ByName(templateName)
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	if existingID < 0 {
+		return nil
 	}
 
+	vmTemplate, err := t.ctrl.Template(existingID).Info(false, false)
+	if err != nil {
+		return fmt.Errorf("Failed to obtain existing VM template: %w", err)
+	}
+	if clusterUID, err := vmTemplate.Template.Get("CLUSTER_UID"); err != nil || clusterUID != t.ResolveName(templateName, false) {
+		return nil
+	}
+
+	if err := t.ctrl.Template(existingID).Delete(); err != nil {
+		return fmt.Errorf("Failed to delete legacy VM template: %w", err)
+	}
+	return nil
+}
+
+// templateOwners also reports whether the template carries CAPONE_TEMPLATE_NAME,
+// templates without it were not created by the provider and are never reference-counted.
+func (t *Templates) templateOwners(templateID int) ([]string, bool, error) {
+	vmTemplate, err := t.ctrl.Template(templateID).Info(false, false)
+	if err != nil {
+		return nil, false, fmt.Errorf("Failed to obtain existing VM template: %w", err)
+	}
+
+	if _, err := vmTemplate.Template.Get("CAPONE_TEMPLATE_NAME"); err != nil {
+		return nil, false, nil
+	}
+	return getOwners(&vmTemplate.Template.Template), true, nil
+}
+
+func (t *Templates) setTemplateOwners(templateID int, owners []string) error {
+	update := goca_dyn.NewTemplate()
+	update.AddPair("CAPONE_OWNERS", strings.Join(owners, ","))
+	if err := t.ctrl.Template(templateID).Update(update.String(), goca_params.Merge); err != nil {
+		return fmt.Errorf("Failed to update VM template: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,127 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+
+	goca "github.com/OpenNebula/one/src/oca/go/src/goca"
+	goca_dyn "github.com/OpenNebula/one/src/oca/go/src/goca/dynamic"
+)
+
+const (
+	VMGroupRoleControlPlane = "control-plane"
+	VMGroupRoleWorker       = "worker"
+)
+
+type VMGroup struct {
+	ctrl *goca.Controller
+	ID   int
+	Name string
+}
+
+type VMGroupOption func(*VMGroup)
+
+func WithVMGroupName(name string) VMGroupOption {
+	return func(g *VMGroup) {
+		g.Name = name
+	}
+}
+
+func NewVMGroup(clients *Clients, options ...VMGroupOption) (*VMGroup, error) {
+	if clients == nil {
+		return nil, fmt.Errorf("clients reference is nil")
+	}
+
+	g := &VMGroup{ctrl: goca.NewController(clients.RPC2), ID: -1}
+	for _, option := range options {
+		option(g)
+	}
+	return g, nil
+}
+
+func (g *VMGroup) Exists() bool {
+	return g.ID >= 0
+}
+
+func (g *VMGroup) ByID(vmGroupID int) error {
+	vmGroup, err := g.ctrl.VMGroup(vmGroupID).Info(false)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch VM group: %w", err)
+	}
+	g.ID = vmGroup.ID
+	g.Name = vmGroup.Name
+
+	return nil
+}
+
+func (g *VMGroup) ByName(vmGroupName string) error {
+	vmGroupID, err := g.ctrl.VMGroups().ByName(vmGroupName)
+	if err != nil {
+		return fmt.Errorf("Failed to fetch VM group: %w", classifyError(err))
+	}
+
+	return g.ByID(vmGroupID)
+}
+
+func (g *VMGroup) FromSpec(vmGroup *infrav1.ONEVMGroup) error {
+	if g.Exists() {
+		return nil
+	}
+
+	vmGroupTemplate := goca_dyn.NewTemplate()
+	vmGroupTemplate.AddPair("NAME", g.Name)
+	vmGroupTemplate.Elements = append(vmGroupTemplate.Elements,
+		generateVMGroupRole(VMGroupRoleControlPlane, vmGroup.ControlPlanePolicy),
+		generateVMGroupRole(VMGroupRoleWorker, vmGroup.WorkerPolicy),
+	)
+
+	vmGroupID, err := g.ctrl.VMGroups().Create(vmGroupTemplate.String())
+	if err != nil {
+		return fmt.Errorf("Failed to create VM group: %w", err)
+	}
+	if err := g.ByID(vmGroupID); err != nil {
+		return fmt.Errorf("Failed to create VM group: %w", err)
+	}
+
+	return nil
+}
+
+func generateVMGroupRole(roleName string, policy infrav1.ONEVMGroupPolicy) *goca_dyn.Vector {
+	roleVec := &goca_dyn.Vector{XMLName: xml.Name{Local: "ROLE"}}
+	roleVec.AddPair("NAME", roleName)
+	if policy != "" && policy != infrav1.VMGroupPolicyNone {
+		roleVec.AddPair("POLICY", string(policy))
+	}
+	return roleVec
+}
+
+func (g *VMGroup) Delete() error {
+	if !g.Exists() {
+		return nil
+	}
+
+	if err := g.ctrl.VMGroup(g.ID).Delete(); err != nil {
+		return fmt.Errorf("Failed to delete VM group: %w", err)
+	}
+
+	g.ID = -1
+	return nil
+}
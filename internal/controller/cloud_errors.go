@@ -0,0 +1,66 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+	"github.com/OpenNebula/cluster-api-provider-opennebula/internal/cloud"
+)
+
+const (
+	cloudAuthFailedRequeueAfter    = 5 * time.Minute
+	cloudQuotaExceededRequeueAfter = 2 * time.Minute
+	cloudConflictRequeueAfter      = 15 * time.Second
+)
+
+// handleCloudError reports OpenNebula errors a quick retry will not fix (bad credentials,
+// exhausted quotas, locked resources) in the CloudAPIReady condition and requeues
+// with a fixed delay, instead of returning them to the exponential backoff.
+func handleCloudError(ctx context.Context, obj conditions.Setter, result ctrl.Result, err error) (ctrl.Result, error) {
+	var (
+		reason       string
+		severity     clusterv1.ConditionSeverity
+		requeueAfter time.Duration
+	)
+	switch {
+	case err == nil:
+		if conditions.Has(obj, infrav1.CloudAPIReadyCondition) {
+			conditions.MarkTrue(obj, infrav1.CloudAPIReadyCondition)
+		}
+		return result, nil
+	case errors.Is(err, cloud.ErrAuthFailed):
+		reason, severity, requeueAfter = infrav1.CloudAuthFailedReason, clusterv1.ConditionSeverityError, cloudAuthFailedRequeueAfter
+	case errors.Is(err, cloud.ErrQuotaExceeded):
+		reason, severity, requeueAfter = infrav1.CloudQuotaExceededReason, clusterv1.ConditionSeverityError, cloudQuotaExceededRequeueAfter
+	case errors.Is(err, cloud.ErrConflict):
+		reason, severity, requeueAfter = infrav1.CloudConflictReason, clusterv1.ConditionSeverityWarning, cloudConflictRequeueAfter
+	default:
+		return result, err
+	}
+
+	conditions.MarkFalse(obj, infrav1.CloudAPIReadyCondition, reason, severity, "%s", err.Error())
+	ctrl.LoggerFrom(ctx).Info("OpenNebula rejected the request, retrying later", "reason", reason, "requeueAfter", requeueAfter, "error", err.Error())
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
@@ -20,20 +20,27 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 
 	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
@@ -44,6 +51,12 @@ import (
 type ONEClusterReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// IdentityNamespace is where the Secrets referenced by ONEClusterIdentities live.
+	IdentityNamespace string
+
+	// ClientsCache is shared by the reconcilers, when nil clients are built on every reconcile.
+	ClientsCache *cloud.ClientsCache
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oneclusters,verbs=get;list;watch;create;update;patch;delete
@@ -51,13 +64,15 @@ type ONEClusterReconciler struct {
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oneclusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=clusters;clusters/status,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=oneclusteridentities,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
-func (r *ONEClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+func (r *ONEClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, rerr error) {
 	log := ctrl.LoggerFrom(ctx)
 
 	oneCluster := &infrav1.ONECluster{}
@@ -86,40 +101,74 @@ func (r *ONEClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	var (
+		externalImages    *cloud.Images
+		externalTemplates *cloud.Templates
+		externalRouter    *cloud.Router
+		externalCleanup   *cloud.Cleanup
+		externalVMGroup   *cloud.VMGroup
+
+		externalVRReservation *cloud.Reservation
+		externalLBReservation *cloud.Reservation
+
+		externalControlPlaneSecurityGroup *cloud.SecurityGroup
+		externalWorkerSecurityGroup       *cloud.SecurityGroup
+
+		externalVIPLease *cloud.Lease
+	)
+
 	defer func() {
+		res, rerr = handleCloudError(ctx, oneCluster, res, rerr)
+
+		conditions.SetSummary(oneCluster,
+			conditions.WithConditions(
+				infrav1.LoadBalancerReadyCondition,
+				infrav1.ImagesReadyCondition,
+				infrav1.CloudAPIReadyCondition,
+			),
+		)
+
 		err := patchHelper.Patch(
 			ctx,
 			oneCluster,
 			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 				clusterv1.ReadyCondition,
+				infrav1.LoadBalancerReadyCondition,
+				infrav1.ImagesReadyCondition,
+				infrav1.CloudAPIReadyCondition,
 			}},
 		)
 		if err != nil {
 			log.Error(err, "Failed to patch ONECluster")
+			// A VIP picked in this pass is only recorded by the patch, release it so it does not leak.
+			if externalVIPLease != nil && externalVIPLease.Picked {
+				if err := externalVIPLease.Release(); err != nil {
+					log.Error(err, "Failed to release control-plane VIP", "ip", externalVIPLease.IP)
+				}
+			}
 			if rerr == nil {
 				rerr = err
 			}
 		}
 	}()
 
-	var (
-		externalImages    *cloud.Images
-		externalTemplates *cloud.Templates
-		externalRouter    *cloud.Router
-		externalCleanup   *cloud.Cleanup
-	)
-	if len(oneCluster.Spec.Images) > 0 || len(oneCluster.Spec.Templates) > 0 || oneCluster.Spec.VirtualRouter != nil {
-		cloudClients, err := cloud.NewClients(ctx, r.Client, oneCluster)
+	if len(oneCluster.Spec.Images) > 0 || len(oneCluster.Spec.Templates) > 0 || len(oneCluster.Status.Templates) > 0 ||
+		oneCluster.Spec.VirtualRouter != nil ||
+		oneCluster.Spec.VMGroup != nil ||
+		oneCluster.Spec.VirtualRouterReservation != nil || oneCluster.Spec.LoadBalancerReservation != nil ||
+		oneCluster.Spec.SecurityGroups != nil ||
+		loadBalancerType(oneCluster) == infrav1.LoadBalancerTypeKubeVIP {
+		cloudClients, err := r.ClientsCache.Get(ctx, r.Client, oneCluster, r.IdentityNamespace)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
 		if len(oneCluster.Spec.Images) > 0 {
-			externalImages, err = cloud.NewImages(cloudClients)
+			externalImages, err = cloud.NewImages(cloudClients, string(oneCluster.UID))
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud images")
 			}
 		}
-		if len(oneCluster.Spec.Templates) > 0 {
+		if len(oneCluster.Spec.Templates) > 0 || len(oneCluster.Status.Templates) > 0 {
 			externalTemplates, err = cloud.NewTemplates(cloudClients, string(oneCluster.UID))
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud templates")
@@ -138,15 +187,75 @@ func (r *ONEClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud router")
 			}
+		}
+		if oneCluster.Spec.VirtualRouterReservation != nil {
+			externalVRReservation, err = cloud.NewReservation(cloudClients,
+				cloud.WithReservationName(fmt.Sprintf("%s-vr", oneCluster.Name)),
+			)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud VR reservation")
+			}
+		}
+		if oneCluster.Spec.LoadBalancerReservation != nil {
+			externalLBReservation, err = cloud.NewReservation(cloudClients,
+				cloud.WithReservationName(fmt.Sprintf("%s-lb", oneCluster.Name)),
+			)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud LB reservation")
+			}
+		}
+		if oneCluster.Spec.SecurityGroups != nil {
+			var nodeNetworkName string
+			if network := clusterMachineNetwork(oneCluster); network != nil {
+				nodeNetworkName = network.Name
+			}
+			externalControlPlaneSecurityGroup, err = cloud.NewSecurityGroup(cloudClients,
+				cloud.WithSecurityGroupName(fmt.Sprintf("%s-control-plane", oneCluster.Name)),
+				cloud.WithSecurityGroupNetworkName(nodeNetworkName),
+			)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud control-plane security group")
+			}
+			externalWorkerSecurityGroup, err = cloud.NewSecurityGroup(cloudClients,
+				cloud.WithSecurityGroupName(fmt.Sprintf("%s-worker", oneCluster.Name)),
+				cloud.WithSecurityGroupNetworkName(nodeNetworkName),
+			)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud worker security group")
+			}
+		}
+		if oneCluster.Spec.VirtualRouter != nil ||
+			oneCluster.Spec.VirtualRouterReservation != nil || oneCluster.Spec.LoadBalancerReservation != nil {
 			externalCleanup, err = cloud.NewCleanup(cloudClients, oneCluster.Name)
 			if err != nil {
 				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud cleanup")
 			}
 		}
+		if loadBalancerType(oneCluster) == infrav1.LoadBalancerTypeKubeVIP {
+			leaseOpts := []cloud.LeaseOption{
+				cloud.WithLeaseIP(oneCluster.Spec.ControlPlaneEndpoint.Host),
+			}
+			if network := networkByRole(oneCluster, kubeVIPNetworkRole(oneCluster)); network != nil {
+				leaseOpts = append(leaseOpts, cloud.WithLeaseNetworkName(network.Name))
+			}
+			externalVIPLease, err = cloud.NewLease(cloudClients, leaseOpts...)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud VIP lease")
+			}
+		}
+		if oneCluster.Spec.VMGroup != nil {
+			externalVMGroup, err = cloud.NewVMGroup(cloudClients,
+				cloud.WithVMGroupName(fmt.Sprintf("%s-vmgroup", oneCluster.Name)),
+			)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to initialize cloud VM group")
+			}
+		}
 	}
 
 	if !oneCluster.DeletionTimestamp.IsZero() {
-		return r.reconcileDelete(ctx, oneCluster, externalRouter, externalCleanup)
+		return r.reconcileDelete(ctx, oneCluster, externalImages, externalTemplates, externalRouter, externalCleanup, externalVMGroup,
+			externalControlPlaneSecurityGroup, externalWorkerSecurityGroup, externalVIPLease)
 	}
 
 	if !controllerutil.ContainsFinalizer(oneCluster, infrav1.ClusterFinalizer) {
@@ -154,106 +263,542 @@ func (r *ONEClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	return r.reconcileNormal(ctx, oneCluster, externalImages, externalTemplates, externalRouter)
+	return r.reconcileNormal(ctx, oneCluster, externalImages, externalTemplates, externalRouter, externalVMGroup,
+		externalVRReservation, externalLBReservation,
+		externalControlPlaneSecurityGroup, externalWorkerSecurityGroup, externalVIPLease)
 }
 
 func (r *ONEClusterReconciler) reconcileNormal(
 	ctx context.Context,
 	oneCluster *infrav1.ONECluster,
-	externalImages *cloud.Images, externalTemplates *cloud.Templates, externalRouter *cloud.Router) (ctrl.Result, error) {
+	externalImages *cloud.Images, externalTemplates *cloud.Templates, externalRouter *cloud.Router,
+	externalVMGroup *cloud.VMGroup,
+	externalVRReservation, externalLBReservation *cloud.Reservation,
+	externalControlPlaneSecurityGroup, externalWorkerSecurityGroup *cloud.SecurityGroup,
+	externalVIPLease *cloud.Lease) (ctrl.Result, error) {
+
+	setFailureDomains(oneCluster)
 
 	if externalImages != nil {
-		imagesReady := true
+		images := []infrav1.ONEImageStatus{}
+		var importing, failed, timedOut []string
 		for _, image := range oneCluster.Spec.Images {
 			if image.ImageName != "" && image.ImageContent != "" {
 				if image.ImageDatastoreId == nil {
 					//default value is set in the CRD openapi spec
 					return ctrl.Result{}, fmt.Errorf("image %s has no datastore ID set", image.ImageName)
 				}
-				if err := externalImages.CreateImage(
+				resolvedName, err := externalImages.CreateImage(
 					image.ImageName,
 					image.ImageContent,
 					*image.ImageDatastoreId,
-				); err != nil {
+				)
+				if err != nil {
 					return ctrl.Result{}, errors.Wrap(err, "failed to create images")
 				}
-				imageReady, _ := externalImages.ImageReady(image.ImageName)
-				imagesReady = imagesReady && imageReady
+				imageState, err := externalImages.ImageState(resolvedName)
+				if err != nil {
+					return ctrl.Result{}, errors.Wrap(err, "failed to get image state")
+				}
+				imageStatus := infrav1.ONEImageStatus{
+					ImageName:    image.ImageName,
+					ResolvedName: resolvedName,
+					ID:           int32(imageState.ID),
+					State:        imageState.State,
+					Message:      imageState.Error,
+				}
+				switch {
+				case imageState.Ready:
+				case imageState.Failed:
+					failed = append(failed, fmt.Sprintf("%s: %s", image.ImageName, imageState.Error))
+				case image.ImportTimeout != nil && time.Since(imageState.Registered) > image.ImportTimeout.Duration:
+					imageStatus.Message = fmt.Sprintf("Image was not imported within %s", image.ImportTimeout.Duration)
+					timedOut = append(timedOut, image.ImageName)
+				default:
+					importing = append(importing, image.ImageName)
+				}
+				images = append(images, imageStatus)
 			}
 		}
-		if !imagesReady {
+		oneCluster.Status.Images = images
+
+		// Failed and timed out imports are terminal, they are retried once the spec changes.
+		switch {
+		case len(failed) > 0:
+			conditions.MarkFalse(oneCluster, infrav1.ImagesReadyCondition, infrav1.ImageImportFailedReason,
+				clusterv1.ConditionSeverityError, "Image import failed: %s", strings.Join(failed, "; "))
+			return ctrl.Result{}, nil
+		case len(timedOut) > 0:
+			conditions.MarkFalse(oneCluster, infrav1.ImagesReadyCondition, infrav1.ImageImportTimeoutReason,
+				clusterv1.ConditionSeverityError, "Image import timed out: %s", strings.Join(timedOut, ", "))
+			return ctrl.Result{}, nil
+		case len(importing) > 0:
+			conditions.MarkFalse(oneCluster, infrav1.ImagesReadyCondition, infrav1.ImagesImportingReason,
+				clusterv1.ConditionSeverityInfo, "Waiting for images: %s", strings.Join(importing, ", "))
 			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 		}
+		conditions.MarkTrue(oneCluster, infrav1.ImagesReadyCondition)
 	}
 
 	if externalTemplates != nil {
+		templates := []infrav1.ONETemplateStatus{}
 		for _, template := range oneCluster.Spec.Templates {
 			if template.TemplateName != "" && template.TemplateContent != "" {
-				if err := externalTemplates.CreateTemplate(
+				resolvedName, err := externalTemplates.CreateTemplate(
 					template.TemplateName,
 					template.TemplateContent,
-				); err != nil {
+					template.Shared,
+				)
+				if err != nil {
 					return ctrl.Result{}, errors.Wrap(err, "failed to create templates")
 				}
+				templates = append(templates, infrav1.ONETemplateStatus{
+					TemplateName: template.TemplateName,
+					ResolvedName: resolvedName,
+					Shared:       template.Shared,
+				})
 			}
 		}
+		// Clean up templates which have been removed from the spec.
+		for _, template := range oneCluster.Status.Templates {
+			if slices.ContainsFunc(templates, func(t infrav1.ONETemplateStatus) bool {
+				return t.ResolvedName == template.ResolvedName
+			}) {
+				continue
+			}
+			if err := externalTemplates.DeleteTemplate(template.TemplateName, template.Shared); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to delete templates")
+			}
+		}
+		oneCluster.Status.Templates = templates
 	}
 
-	if externalRouter != nil {
-		externalRouter.ByName(externalRouter.Name)
-		if !externalRouter.Exists() {
-			if err := externalRouter.FromTemplate(
-				oneCluster.Spec.VirtualRouter,
-				oneCluster.Spec.PublicNetwork,
-				oneCluster.Spec.PrivateNetwork,
-			); err != nil {
-				return ctrl.Result{}, errors.Wrap(err, "failed to create VR")
+	if externalVMGroup != nil {
+		if err := externalVMGroup.ByName(externalVMGroup.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to fetch VM group")
+		}
+		if !externalVMGroup.Exists() {
+			if err := externalVMGroup.FromSpec(oneCluster.Spec.VMGroup); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to create VM group")
 			}
+		}
+	}
 
-			if oneCluster.Spec.ControlPlaneEndpoint.Host == "" {
-				if len(externalRouter.FloatingIPs) > 0 && net.ParseIP(externalRouter.FloatingIPs[0]) != nil {
-					oneCluster.Spec.ControlPlaneEndpoint.Host = externalRouter.FloatingIPs[0]
-				}
-			}
+	if externalControlPlaneSecurityGroup != nil && externalWorkerSecurityGroup != nil {
+		err := externalControlPlaneSecurityGroup.ByName(externalControlPlaneSecurityGroup.Name)
+		if err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to fetch control-plane security group")
+		}
+		if err := externalControlPlaneSecurityGroup.FromSpec(
+			oneCluster.Spec.SecurityGroups,
+			cloud.SecurityGroupRoleControlPlane,
+		); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile control-plane security group")
+		}
+		err = externalWorkerSecurityGroup.ByName(externalWorkerSecurityGroup.Name)
+		if err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to fetch worker security group")
+		}
+		if err := externalWorkerSecurityGroup.FromSpec(
+			oneCluster.Spec.SecurityGroups,
+			cloud.SecurityGroupRoleWorker,
+		); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to reconcile worker security group")
+		}
+		oneCluster.Status.SecurityGroups = &infrav1.ONESecurityGroupsStatus{
+			ControlPlaneID: int32(externalControlPlaneSecurityGroup.ID),
+			WorkerID:       int32(externalWorkerSecurityGroup.ID),
+		}
+	}
 
-			if oneCluster.Spec.PrivateNetwork != nil {
-				if oneCluster.Spec.PrivateNetwork.FloatingIP == nil {
-					ipIndex := 0
-					if oneCluster.Spec.PublicNetwork != nil {
-						ipIndex++
-					}
-					oneCluster.Spec.PrivateNetwork.FloatingIP = &externalRouter.FloatingIPs[ipIndex]
-				}
-				if oneCluster.Spec.PrivateNetwork.Gateway == nil {
-					oneCluster.Spec.PrivateNetwork.Gateway = oneCluster.Spec.PrivateNetwork.FloatingIP
-				}
-				if oneCluster.Spec.PrivateNetwork.DNS == nil {
-					oneCluster.Spec.PrivateNetwork.DNS = oneCluster.Spec.PrivateNetwork.FloatingIP
-				}
-			}
+	if externalVRReservation != nil {
+		status, err := reconcileReservation(oneCluster, externalVRReservation, oneCluster.Spec.VirtualRouterReservation)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to create VR reservation")
 		}
+		oneCluster.Status.VirtualRouterReservation = status
 	}
 
-	if oneCluster.Spec.ControlPlaneEndpoint.Host == "" {
-		return ctrl.Result{}, fmt.Errorf("Spec.ControlPlaneEndpoint.Host must not be empty")
+	if externalLBReservation != nil {
+		status, err := reconcileReservation(oneCluster, externalLBReservation, oneCluster.Spec.LoadBalancerReservation)
+		if err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to create LB reservation")
+		}
+		oneCluster.Status.LoadBalancerReservation = status
 	}
 
-	// TODO: use webhook?
-	if oneCluster.Spec.ControlPlaneEndpoint.Port == 0 {
-		oneCluster.Spec.ControlPlaneEndpoint.Port = 6443
+	switch loadBalancerType(oneCluster) {
+	case infrav1.LoadBalancerTypeVirtualRouter:
+		if externalRouter == nil {
+			return ctrl.Result{}, fmt.Errorf("loadBalancer type VirtualRouter requires Spec.VirtualRouter")
+		}
+		if err := r.reconcileVirtualRouter(oneCluster, externalRouter, externalVRReservation); err != nil {
+			return ctrl.Result{}, err
+		}
+	case infrav1.LoadBalancerTypeKubeVIP:
+		if err := r.reconcileKubeVIP(oneCluster, externalVIPLease); err != nil {
+			return ctrl.Result{}, err
+		}
+	case infrav1.LoadBalancerTypeExternal:
+		if oneCluster.Spec.ControlPlaneEndpoint.Host == "" {
+			conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerEndpointInvalidReason,
+				clusterv1.ConditionSeverityError, "Spec.ControlPlaneEndpoint.Host must not be empty")
+			return ctrl.Result{}, fmt.Errorf("Spec.ControlPlaneEndpoint.Host must not be empty")
+		}
+		if oneCluster.Spec.ControlPlaneEndpoint.Port == 0 {
+			oneCluster.Spec.ControlPlaneEndpoint.Port = 6443
+		}
+		oneCluster.Status.LoadBalancer = &infrav1.ONELoadBalancerStatus{
+			Type:     infrav1.LoadBalancerTypeExternal,
+			Endpoint: oneCluster.Spec.ControlPlaneEndpoint,
+		}
 	}
+	conditions.MarkTrue(oneCluster, infrav1.LoadBalancerReadyCondition)
+
+	setNetworkStatus(oneCluster, externalRouter)
 
 	oneCluster.Status.Ready = true
 	return ctrl.Result{}, nil
 }
 
+func (r *ONEClusterReconciler) reconcileVirtualRouter(
+	oneCluster *infrav1.ONECluster,
+	externalRouter *cloud.Router, externalVRReservation *cloud.Reservation) error {
+
+	virtualRouter := oneCluster.Spec.VirtualRouter.DeepCopy()
+	virtualRouter.TemplateName = resolveTemplateName(oneCluster, virtualRouter.TemplateName)
+
+	if err := externalRouter.ByName(externalRouter.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+		return errors.Wrap(err, "failed to fetch VR")
+	}
+	if !externalRouter.Exists() {
+		// Allocate VR floating IPs from the VR reservation (if requested).
+		publicNetwork := oneCluster.Spec.PublicNetwork
+		privateNetwork := oneCluster.Spec.PrivateNetwork
+		if externalVRReservation != nil {
+			switch oneCluster.Spec.VirtualRouterReservation.Network {
+			case infrav1.NetworkRolePublic:
+				publicNetwork = publicNetwork.DeepCopy()
+				publicNetwork.Name = externalVRReservation.Name
+			case infrav1.NetworkRolePrivate:
+				privateNetwork = privateNetwork.DeepCopy()
+				privateNetwork.Name = externalVRReservation.Name
+			}
+		}
+		if err := externalRouter.FromTemplate(
+			virtualRouter,
+			publicNetwork,
+			privateNetwork,
+		); err != nil {
+			conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerProvisioningFailedReason,
+				clusterv1.ConditionSeverityWarning, "%s", err.Error())
+			return errors.Wrap(err, "failed to create VR")
+		}
+
+	} else if err := externalRouter.Sync(virtualRouter); err != nil {
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerProvisioningFailedReason,
+			clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		return errors.Wrap(err, "failed to reconcile VR")
+	}
+
+	// NOTE: The infrastructure cluster contract requires the provider to populate
+	// Spec.ControlPlaneEndpoint, it is the only spec field written by the controller.
+	// IPv4 is preferred for the endpoint in dual-stack setups.
+	floatingIPs := append(slices.Clone(externalRouter.FloatingIPs), externalRouter.FloatingIP6s...)
+	endpoint := &oneCluster.Spec.ControlPlaneEndpoint
+	if endpoint.Host == "" {
+		if len(floatingIPs) > 0 && net.ParseIP(floatingIPs[0]) != nil {
+			endpoint.Host = floatingIPs[0]
+		}
+	} else if ip := net.ParseIP(endpoint.Host); ip != nil && !slices.ContainsFunc(floatingIPs, func(floatingIP string) bool {
+		return ip.Equal(net.ParseIP(floatingIP))
+	}) {
+		// Hostnames cannot be verified here, only literal addresses.
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerEndpointInvalidReason,
+			clusterv1.ConditionSeverityError, "Control-plane endpoint %s is not a floating IP of VR %s", endpoint.Host, externalRouter.Name)
+		return fmt.Errorf("control-plane endpoint %s is not a floating IP of VR %s", endpoint.Host, externalRouter.Name)
+	}
+	if endpoint.Host == "" {
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerEndpointInvalidReason,
+			clusterv1.ConditionSeverityError, "VR %s has no floating IP to use as control-plane endpoint", externalRouter.Name)
+		return fmt.Errorf("Spec.ControlPlaneEndpoint.Host must not be empty")
+	}
+	if endpoint.Port == 0 {
+		endpoint.Port = virtualRouterAPIServerPort(oneCluster.Spec.VirtualRouter)
+	}
+
+	vrID := int32(externalRouter.ID)
+	oneCluster.Status.LoadBalancer = &infrav1.ONELoadBalancerStatus{
+		Type:            infrav1.LoadBalancerTypeVirtualRouter,
+		Endpoint:        *endpoint,
+		VirtualRouterID: &vrID,
+		FloatingIPs:     floatingIPs,
+	}
+	return nil
+}
+
+func (r *ONEClusterReconciler) reconcileKubeVIP(oneCluster *infrav1.ONECluster, externalVIPLease *cloud.Lease) error {
+	networkRole := kubeVIPNetworkRole(oneCluster)
+	if networkByRole(oneCluster, networkRole) == nil {
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerEndpointInvalidReason,
+			clusterv1.ConditionSeverityError, "KubeVIP requires the %s network to be defined", networkRole)
+		return fmt.Errorf("KubeVIP requires the %s network to be defined", networkRole)
+	}
+
+	endpoint := &oneCluster.Spec.ControlPlaneEndpoint
+	if endpoint.Host != "" && net.ParseIP(endpoint.Host) == nil {
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerEndpointInvalidReason,
+			clusterv1.ConditionSeverityError, "KubeVIP requires an IP address as control-plane endpoint, got %q", endpoint.Host)
+		return fmt.Errorf("KubeVIP requires an IP address as control-plane endpoint, got %q", endpoint.Host)
+	}
+
+	// Release the previously held address when the endpoint has been changed.
+	if status := oneCluster.Status.LoadBalancer; status != nil && status.LeaseNetworkID != nil &&
+		status.Endpoint.Host != "" && status.Endpoint.Host != endpoint.Host {
+		if err := r.releaseVIPLease(oneCluster, externalVIPLease); err != nil {
+			return err
+		}
+	}
+
+	if err := externalVIPLease.Hold(); err != nil {
+		conditions.MarkFalse(oneCluster, infrav1.LoadBalancerReadyCondition, infrav1.LoadBalancerProvisioningFailedReason,
+			clusterv1.ConditionSeverityWarning, "%s", err.Error())
+		return errors.Wrap(err, "failed to hold control-plane VIP")
+	}
+	endpoint.Host = externalVIPLease.IP
+	if endpoint.Port == 0 {
+		endpoint.Port = 6443
+	}
+
+	// Addresses outside the network ranges are not held, so there is nothing to release later.
+	var leaseNetworkID *int32
+	if externalVIPLease.Held {
+		networkID := int32(externalVIPLease.NetworkID)
+		leaseNetworkID = &networkID
+	}
+	oneCluster.Status.LoadBalancer = &infrav1.ONELoadBalancerStatus{
+		Type:           infrav1.LoadBalancerTypeKubeVIP,
+		Endpoint:       *endpoint,
+		LeaseNetworkID: leaseNetworkID,
+	}
+	return nil
+}
+
+// releaseVIPLease releases the address recorded in status, which may differ from the one in spec.
+func (r *ONEClusterReconciler) releaseVIPLease(oneCluster *infrav1.ONECluster, externalVIPLease *cloud.Lease) error {
+	status := oneCluster.Status.LoadBalancer
+	if externalVIPLease == nil || status == nil || status.LeaseNetworkID == nil {
+		return nil
+	}
+
+	heldLease := *externalVIPLease
+	heldLease.NetworkID = int(*status.LeaseNetworkID)
+	heldLease.IP = status.Endpoint.Host
+	if err := heldLease.Release(); err != nil {
+		return errors.Wrap(err, "failed to release control-plane VIP")
+	}
+	status.LeaseNetworkID = nil
+	return nil
+}
+
+func kubeVIPNetworkRole(oneCluster *infrav1.ONECluster) infrav1.ONENetworkRole {
+	if oneCluster.Spec.LoadBalancer != nil && oneCluster.Spec.LoadBalancer.KubeVIP != nil &&
+		oneCluster.Spec.LoadBalancer.KubeVIP.Network != "" {
+		return oneCluster.Spec.LoadBalancer.KubeVIP.Network
+	}
+	return infrav1.NetworkRolePublic
+}
+
+// resolveTemplateName maps a VM template name onto the template created for the
+// cluster, names of templates not managed by the cluster are returned as is.
+func resolveTemplateName(oneCluster *infrav1.ONECluster, templateName string) string {
+	for _, template := range oneCluster.Status.Templates {
+		if template.TemplateName == templateName {
+			return template.ResolvedName
+		}
+	}
+	return templateName
+}
+
+func loadBalancerType(oneCluster *infrav1.ONECluster) infrav1.ONELoadBalancerType {
+	if oneCluster.Spec.LoadBalancer != nil && oneCluster.Spec.LoadBalancer.Type != "" {
+		return oneCluster.Spec.LoadBalancer.Type
+	}
+	if oneCluster.Spec.VirtualRouter != nil {
+		return infrav1.LoadBalancerTypeVirtualRouter
+	}
+	return infrav1.LoadBalancerTypeExternal
+}
+
+// virtualRouterAPIServerPort returns the frontend port forwarded to the kubernetes api.
+func virtualRouterAPIServerPort(virtualRouter *infrav1.ONEVirtualRouter) int32 {
+	for _, listener := range virtualRouter.Listeners {
+		if (listener.TargetPort != nil && *listener.TargetPort == 6443) ||
+			(listener.TargetPort == nil && listener.Port == 6443) {
+			return listener.Port
+		}
+	}
+	return 6443
+}
+
+func networkByRole(oneCluster *infrav1.ONECluster, role infrav1.ONENetworkRole) *infrav1.ONEVirtualNetwork {
+	switch role {
+	case infrav1.NetworkRolePublic:
+		return oneCluster.Spec.PublicNetwork
+	case infrav1.NetworkRolePrivate:
+		return oneCluster.Spec.PrivateNetwork
+	}
+	return nil
+}
+
+func reconcileReservation(
+	oneCluster *infrav1.ONECluster,
+	externalReservation *cloud.Reservation, reservation *infrav1.ONEReservation) (*infrav1.ONEReservationStatus, error) {
+
+	network := networkByRole(oneCluster, reservation.Network)
+	if network == nil {
+		return nil, fmt.Errorf("reservation %s requires the %s network to be defined", externalReservation.Name, reservation.Network)
+	}
+
+	if err := externalReservation.ByName(externalReservation.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+		return nil, errors.Wrap(err, "failed to fetch reservation")
+	}
+	if !externalReservation.Exists() {
+		if err := externalReservation.FromNetwork(network.Name, int(reservation.Size)); err != nil {
+			return nil, err
+		}
+	}
+
+	status := &infrav1.ONEReservationStatus{
+		ID:              int32(externalReservation.ID),
+		Name:            externalReservation.Name,
+		ParentNetworkID: int32(externalReservation.ParentNetworkID),
+	}
+	for _, ar := range externalReservation.Ranges {
+		status.Ranges = append(status.Ranges, infrav1.ONEAddressRange{IP: ar.IP, Size: int32(ar.Size)})
+	}
+	return status, nil
+}
+
+func setNetworkStatus(oneCluster *infrav1.ONECluster, externalRouter *cloud.Router) {
+	status := &infrav1.ONENetworkStatus{}
+
+	var routerNICs []cloud.RouterNIC
+	if externalRouter != nil && externalRouter.Exists() {
+		vrID := int32(externalRouter.ID)
+		status.VirtualRouterID = &vrID
+		for _, vmID := range externalRouter.VMIDs {
+			status.VirtualRouterVMIDs = append(status.VirtualRouterVMIDs, int32(vmID))
+		}
+		routerNICs = externalRouter.NICs
+	}
+	routerNIC := func(nicIndex int) *cloud.RouterNIC {
+		if nicIndex < len(routerNICs) {
+			return &routerNICs[nicIndex]
+		}
+		return nil
+	}
+
+	// VR NICs follow the public, private network order (see Router.FromTemplate).
+	nicIndex := 0
+	if oneCluster.Spec.PublicNetwork != nil {
+		status.Public = generateVirtualNetworkStatus(oneCluster.Spec.PublicNetwork, routerNIC(nicIndex), false)
+		nicIndex++
+	}
+	if oneCluster.Spec.PrivateNetwork != nil {
+		status.Private = generateVirtualNetworkStatus(oneCluster.Spec.PrivateNetwork, routerNIC(nicIndex), true)
+	}
+
+	oneCluster.Status.Network = status
+}
+
+func generateVirtualNetworkStatus(
+	network *infrav1.ONEVirtualNetwork,
+	routerNIC *cloud.RouterNIC, private bool) *infrav1.ONEVirtualNetworkStatus {
+
+	status := &infrav1.ONEVirtualNetworkStatus{Name: network.Name}
+	if network.FloatingIP != nil {
+		status.FloatingIP = *network.FloatingIP
+	}
+	if network.FloatingIP6 != nil {
+		status.FloatingIP6 = *network.FloatingIP6
+	}
+	if routerNIC != nil {
+		// The VR NIC may be attached to a reservation instead of the network itself.
+		if routerNIC.NetworkName == network.Name && routerNIC.NetworkID >= 0 {
+			networkID := int32(routerNIC.NetworkID)
+			status.ID = &networkID
+		}
+		if routerNIC.FloatingIP != "" {
+			status.FloatingIP = routerNIC.FloatingIP
+		}
+		if routerNIC.FloatingIP6 != "" {
+			status.FloatingIP6 = routerNIC.FloatingIP6
+		}
+	}
+
+	if network.Gateway != nil {
+		status.Gateway = *network.Gateway
+	}
+	if network.Gateway6 != nil {
+		status.Gateway6 = *network.Gateway6
+	}
+	if network.DNS != nil {
+		status.DNS = *network.DNS
+	}
+	// Machines in the private network are routed and resolved through the VR.
+	if private && routerNIC != nil {
+		if status.Gateway == "" {
+			status.Gateway = status.FloatingIP
+		}
+		if status.Gateway6 == "" {
+			status.Gateway6 = status.FloatingIP6
+		}
+		if status.DNS == "" {
+			status.DNS = status.FloatingIP
+		}
+	}
+	return status
+}
+
+func setFailureDomains(oneCluster *infrav1.ONECluster) {
+	if len(oneCluster.Spec.FailureDomains) == 0 {
+		oneCluster.Status.FailureDomains = nil
+		return
+	}
+
+	oneCluster.Status.FailureDomains = clusterv1.FailureDomains{}
+	for _, failureDomain := range oneCluster.Spec.FailureDomains {
+		attributes := map[string]string{
+			"clusterID": strconv.Itoa(int(failureDomain.ClusterID)),
+		}
+		if failureDomain.HostRequirements != nil {
+			attributes["hostRequirements"] = *failureDomain.HostRequirements
+		}
+		if failureDomain.DatastoreRequirements != nil {
+			attributes["datastoreRequirements"] = *failureDomain.DatastoreRequirements
+		}
+		oneCluster.Status.FailureDomains[failureDomain.Name] = clusterv1.FailureDomainSpec{
+			ControlPlane: failureDomain.ControlPlane == nil || *failureDomain.ControlPlane,
+			Attributes:   attributes,
+		}
+	}
+}
+
 func (r *ONEClusterReconciler) reconcileDelete(
 	ctx context.Context,
 	oneCluster *infrav1.ONECluster,
-	externalRouter *cloud.Router, externalCleanup *cloud.Cleanup) (ctrl.Result, error) {
+	externalImages *cloud.Images, externalTemplates *cloud.Templates,
+	externalRouter *cloud.Router, externalCleanup *cloud.Cleanup, externalVMGroup *cloud.VMGroup,
+	externalControlPlaneSecurityGroup, externalWorkerSecurityGroup *cloud.SecurityGroup,
+	externalVIPLease *cloud.Lease) (ctrl.Result, error) {
+
+	log := ctrl.LoggerFrom(ctx)
 
 	if externalRouter != nil {
-		externalRouter.ByName(externalRouter.Name)
+		if err := externalRouter.ByName(externalRouter.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to fetch VR")
+		}
 		if err := externalRouter.Delete(); err != nil {
 			return ctrl.Result{}, errors.Wrap(err, "failed to delete VR")
 		}
@@ -271,12 +816,155 @@ func (r *ONEClusterReconciler) reconcileDelete(
 		}
 	}
 
+	if externalVMGroup != nil {
+		if err := externalVMGroup.ByName(externalVMGroup.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to fetch VM group")
+		}
+		if err := externalVMGroup.Delete(); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete VM group")
+		}
+	}
+
+	for _, externalSecurityGroup := range []*cloud.SecurityGroup{
+		externalControlPlaneSecurityGroup,
+		externalWorkerSecurityGroup,
+	} {
+		if externalSecurityGroup != nil {
+			err := externalSecurityGroup.ByName(externalSecurityGroup.Name)
+			if err != nil && !errors.Is(err, cloud.ErrNotFound) {
+				return ctrl.Result{}, errors.Wrap(err, "failed to fetch security group")
+			}
+			if err := externalSecurityGroup.Delete(); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to delete security group")
+			}
+		}
+	}
+
+	if err := r.releaseVIPLease(oneCluster, externalVIPLease); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if externalTemplates != nil {
+		templates := slices.Clone(oneCluster.Status.Templates)
+		for _, template := range oneCluster.Spec.Templates {
+			if template.TemplateName != "" && !slices.ContainsFunc(templates, func(t infrav1.ONETemplateStatus) bool {
+				return t.TemplateName == template.TemplateName
+			}) {
+				templates = append(templates, infrav1.ONETemplateStatus{
+					TemplateName: template.TemplateName,
+					Shared:       template.Shared,
+				})
+			}
+		}
+		for _, template := range templates {
+			if err := externalTemplates.DeleteTemplate(template.TemplateName, template.Shared); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to delete templates")
+			}
+		}
+		oneCluster.Status.Templates = nil
+	}
+
+	if externalImages != nil {
+		for _, image := range oneCluster.Spec.Images {
+			if image.ImageName == "" || image.ReclaimPolicy != infrav1.ImageReclaimPolicyDelete {
+				continue
+			}
+			usedByVMs, err := externalImages.DeleteImage(image.ImageName)
+			if err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "failed to delete image")
+			}
+			if usedByVMs > 0 {
+				log.Info("Waiting for image to be released by VMs", "image", image.ImageName, "vms", usedByVMs)
+				return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+			}
+		}
+	}
+
+	r.ClientsCache.Invalidate(oneCluster.UID)
+
 	controllerutil.RemoveFinalizer(oneCluster, infrav1.ClusterFinalizer)
 	return ctrl.Result{}, nil
 }
 
+const (
+	// identityRefNameField indexes ONEClusters by the ONEClusterIdentity they reference.
+	identityRefNameField = "spec.identityRef.name"
+	// identitySecretNameField indexes ONEClusterIdentities by the Secret they reference.
+	identitySecretNameField = "spec.secretName"
+)
+
 func (r *ONEClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	ctx := context.Background()
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &infrav1.ONECluster{}, identityRefNameField, indexIdentityRefName); err != nil {
+		return errors.Wrap(err, "failed to index ONEClusters by identity")
+	}
+	if err := mgr.GetFieldIndexer().IndexField(ctx, &infrav1.ONEClusterIdentity{}, identitySecretNameField, indexIdentitySecretName); err != nil {
+		return errors.Wrap(err, "failed to index ONEClusterIdentities by secret")
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&infrav1.ONECluster{}).
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToONEClusters),
+		).
 		Complete(r)
 }
+
+func indexIdentityRefName(o client.Object) []string {
+	oneCluster, ok := o.(*infrav1.ONECluster)
+	if !ok || oneCluster.Spec.IdentityRef == nil {
+		return nil
+	}
+	return []string{oneCluster.Spec.IdentityRef.Name}
+}
+
+func indexIdentitySecretName(o client.Object) []string {
+	identity, ok := o.(*infrav1.ONEClusterIdentity)
+	if !ok || identity.Spec.SecretName == "" {
+		return nil
+	}
+	return []string{identity.Spec.SecretName}
+}
+
+// secretToONEClusters requeues the ONEClusters owning a credentials Secret, or
+// using it through a ONEClusterIdentity, and drops their cached clients, so
+// rotated credentials are picked up right away.
+func (r *ONEClusterReconciler) secretToONEClusters(ctx context.Context, o client.Object) []reconcile.Request {
+	requests := []reconcile.Request{}
+	for _, ownerRef := range o.GetOwnerReferences() {
+		if ownerRef.APIVersion != infrav1.GroupVersion.String() || ownerRef.Kind != "ONECluster" {
+			continue
+		}
+		r.ClientsCache.Invalidate(ownerRef.UID)
+		requests = append(requests, reconcile.Request{
+			NamespacedName: client.ObjectKey{Namespace: o.GetNamespace(), Name: ownerRef.Name},
+		})
+	}
+
+	if r.IdentityNamespace == "" || o.GetNamespace() != r.IdentityNamespace {
+		return requests
+	}
+
+	log := ctrl.LoggerFrom(ctx)
+
+	var identities infrav1.ONEClusterIdentityList
+	if err := r.List(ctx, &identities, client.MatchingFields{identitySecretNameField: o.GetName()}); err != nil {
+		log.Error(err, "Failed to list ONEClusterIdentities", "secret", o.GetName())
+		return requests
+	}
+	for _, identity := range identities.Items {
+		var oneClusters infrav1.ONEClusterList
+		if err := r.List(ctx, &oneClusters, client.MatchingFields{identityRefNameField: identity.Name}); err != nil {
+			log.Error(err, "Failed to list ONEClusters", "identity", identity.Name)
+			continue
+		}
+		for _, oneCluster := range oneClusters.Items {
+			r.ClientsCache.Invalidate(oneCluster.UID)
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&oneCluster),
+			})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+)
+
+func TestSecretToONEClusters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := infrav1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	identity := func(name, secretName string) *infrav1.ONEClusterIdentity {
+		return &infrav1.ONEClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       infrav1.ONEClusterIdentitySpec{SecretName: secretName},
+		}
+	}
+	oneCluster := func(namespace, name, identityName string) *infrav1.ONECluster {
+		c := &infrav1.ONECluster{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+		if identityName != "" {
+			c.Spec.IdentityRef = &infrav1.ONEClusterIdentityReference{Name: identityName}
+		}
+		return c
+	}
+	request := func(namespace, name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: name}}
+	}
+
+	objects := []client.Object{
+		identity("shared", "one-creds"),
+		identity("other", "other-creds"),
+		oneCluster("ns1", "a", "shared"),
+		oneCluster("ns2", "b", "shared"),
+		oneCluster("ns1", "c", "other"),
+		oneCluster("ns1", "d", ""),
+	}
+
+	tests := []struct {
+		name     string
+		secret   *corev1.Secret
+		expected []reconcile.Request
+	}{
+		{
+			name: "owned secret",
+			secret: &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+				Namespace: "ns1",
+				Name:      "d-creds",
+				OwnerReferences: []metav1.OwnerReference{
+					{APIVersion: infrav1.GroupVersion.String(), Kind: "ONECluster", Name: "d"},
+				},
+			}},
+			expected: []reconcile.Request{request("ns1", "d")},
+		},
+		{
+			name:     "identity secret",
+			secret:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "capone-system", Name: "one-creds"}},
+			expected: []reconcile.Request{request("ns1", "a"), request("ns2", "b")},
+		},
+		{
+			name:     "identity secret name outside the identity namespace",
+			secret:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "one-creds"}},
+			expected: []reconcile.Request{},
+		},
+		{
+			name:     "unreferenced secret",
+			secret:   &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "capone-system", Name: "unused"}},
+			expected: []reconcile.Request{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &ONEClusterReconciler{
+				Client: fake.NewClientBuilder().
+					WithScheme(scheme).
+					WithObjects(objects...).
+					WithIndex(&infrav1.ONECluster{}, identityRefNameField, indexIdentityRefName).
+					WithIndex(&infrav1.ONEClusterIdentity{}, identitySecretNameField, indexIdentitySecretName).
+					Build(),
+				IdentityNamespace: "capone-system",
+			}
+
+			requests := r.secretToONEClusters(context.Background(), tt.secret)
+			if !reflect.DeepEqual(requests, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, requests)
+			}
+		})
+	}
+}
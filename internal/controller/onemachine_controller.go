@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
@@ -34,6 +35,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	capierrors "sigs.k8s.io/cluster-api/errors"
 	utilexp "sigs.k8s.io/cluster-api/exp/util"
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
@@ -50,6 +52,19 @@ import (
 type ONEMachineReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+
+	// ProvisioningTimeout is how long a VM may take to reach RUNNING, zero disables it.
+	ProvisioningTimeout time.Duration
+
+	// TerminationGracePeriod is how long to wait after a graceful terminate
+	// before falling back to a hard terminate, zero always terminates hard.
+	TerminationGracePeriod time.Duration
+
+	// IdentityNamespace is where the Secrets referenced by ONEClusterIdentities live.
+	IdentityNamespace string
+
+	// ClientsCache is shared by the reconcilers, when nil clients are built on every reconcile.
+	ClientsCache *cloud.ClientsCache
 }
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=onemachines,verbs=get;list;watch;create;update;patch;delete
@@ -64,7 +79,7 @@ type ONEMachineReconciler struct {
 //
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.19.0/pkg/reconcile
-func (r *ONEMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, rerr error) {
+func (r *ONEMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, rerr error) {
 	log := log.FromContext(ctx)
 
 	oneMachine := &infrav1.ONEMachine{}
@@ -124,11 +139,22 @@ func (r *ONEMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 	defer func() {
+		res, rerr = handleCloudError(ctx, oneMachine, res, rerr)
+
+		conditions.SetSummary(oneMachine,
+			conditions.WithConditions(
+				infrav1.InstanceReadyCondition,
+				infrav1.CloudAPIReadyCondition,
+			),
+		)
+
 		err := patchHelper.Patch(
 			ctx,
 			oneMachine,
 			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
 				clusterv1.ReadyCondition,
+				infrav1.InstanceReadyCondition,
+				infrav1.CloudAPIReadyCondition,
 			}},
 		)
 		if err != nil {
@@ -144,22 +170,48 @@ func (r *ONEMachineReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, nil
 	}
 
-	cloudClients, err := cloud.NewClients(ctx, r.Client, oneCluster)
+	cloudClients, err := r.ClientsCache.Get(ctx, r.Client, oneCluster, r.IdentityNamespace)
 	if err != nil {
 		return ctrl.Result{}, err
 	}
 	machineOpts := []cloud.MachineOption{
 		cloud.WithMachineName(generateExternalMachineName(machine, oneMachine)),
 	}
-	if oneCluster.Spec.VirtualRouter != nil {
-		externalRouter, err := cloud.NewRouter(cloudClients)
-		if err != nil {
-			return ctrl.Result{}, fmt.Errorf("failed to initialize cloud router: %w", err)
+	if oneMachine.Spec.CPU != nil {
+		machineOpts = append(machineOpts, cloud.WithMachineCPU(oneMachine.Spec.CPU.AsApproximateFloat64()))
+	}
+	if oneMachine.Spec.VCPU != nil {
+		machineOpts = append(machineOpts, cloud.WithMachineVCPU(int(*oneMachine.Spec.VCPU)))
+	}
+	if oneMachine.Spec.MemoryMB != nil {
+		machineOpts = append(machineOpts, cloud.WithMachineMemoryMB(int(*oneMachine.Spec.MemoryMB)))
+	}
+	if oneMachine.Spec.RootDiskSizeMB != nil {
+		machineOpts = append(machineOpts, cloud.WithMachineRootDiskSizeMB(int(*oneMachine.Spec.RootDiskSizeMB)))
+	}
+	if len(oneMachine.Spec.Disks) > 0 {
+		machineOpts = append(machineOpts, cloud.WithMachineDisks(oneMachine.Spec.Disks))
+	}
+	if len(oneMachine.Spec.Networks) > 0 {
+		machineOpts = append(machineOpts, cloud.WithMachineNetworks(oneMachine.Spec.Networks))
+	}
+	if len(oneCluster.Status.Images) > 0 {
+		imageNames := map[string]string{}
+		for _, image := range oneCluster.Status.Images {
+			if image.ResolvedName != "" {
+				imageNames[image.ImageName] = image.ResolvedName
+			}
 		}
-		externalRouter.ByName(fmt.Sprintf("%s-cp", oneCluster.Name))
-		if externalRouter.Exists() {
-			machineOpts = append(machineOpts, cloud.WithMachineRouterID(externalRouter.ID))
+		machineOpts = append(machineOpts, cloud.WithMachineImageNames(imageNames))
+	}
+	// Placement only matters when creating the VM, a failure domain removed from
+	// the ONECluster or a failing lookup must not block deletion.
+	if oneMachine.ObjectMeta.DeletionTimestamp.IsZero() {
+		placementOpts, err := machinePlacementOptions(cloudClients, oneCluster, machine)
+		if err != nil {
+			return ctrl.Result{}, err
 		}
+		machineOpts = append(machineOpts, placementOpts...)
 	}
 	externalMachine, err := cloud.NewMachine(cloudClients, machineOpts...)
 	if err != nil {
@@ -180,6 +232,11 @@ func (r *ONEMachineReconciler) reconcileNormal(
 
 	log := log.FromContext(ctx)
 
+	if oneMachine.Status.FailureReason != nil {
+		log.Info("ONEMachine is in a failed state, skipping reconciliation", "reason", *oneMachine.Status.FailureReason)
+		return ctrl.Result{}, nil
+	}
+
 	if !cluster.Status.InfrastructureReady {
 		log.Info("Waiting for Cluster Controller to create cluster infrastructure")
 		return ctrl.Result{}, nil
@@ -202,11 +259,30 @@ func (r *ONEMachineReconciler) reconcileNormal(
 	}
 
 	if oneMachine.Spec.ProviderID != nil {
-		if err := externalMachine.ByName(externalMachine.Name); err != nil {
+		if err := externalMachine.ByProviderID(*oneMachine.Spec.ProviderID); err != nil {
 			return ctrl.Result{}, err
 		}
+		if setMachineInstanceState(oneMachine, externalMachine) {
+			return ctrl.Result{}, nil
+		}
+		setMachineAddresses(oneCluster, oneMachine, externalMachine)
+
+		// Keep VR backend params in sync with the (possibly updated) VR listeners.
+		if util.IsControlPlaneMachine(machine) && oneCluster.Spec.VirtualRouter != nil && externalMachine.RouterID >= 0 {
+			if err := externalMachine.UpdateRouterBackend(oneCluster.Spec.VirtualRouter); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		if !externalMachine.Running() {
+			// NOTE: the machine has been running before, keep it ready (e.g. during live migration).
+			state, lcmState := externalMachine.StateString()
+			conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceNotRunningReason,
+				clusterv1.ConditionSeverityWarning, "VM is in %s %s state", state, lcmState)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
 
-		setMachineAddress(oneMachine, externalMachine.Address4)
+		conditions.MarkTrue(oneMachine, infrav1.InstanceReadyCondition)
 		oneMachine.Status.Ready = true
 		return ctrl.Result{}, nil
 	}
@@ -230,14 +306,11 @@ func (r *ONEMachineReconciler) reconcileNormal(
 		return ctrl.Result{}, errors.Wrap(err, "Failed to get data secret")
 	}
 
-	externalMachine.ByName(externalMachine.Name)
+	if err := externalMachine.ByName(externalMachine.Name); err != nil && !errors.Is(err, cloud.ErrNotFound) {
+		return ctrl.Result{}, errors.Wrap(err, "failed to fetch VM")
+	}
 	if !externalMachine.Exists() {
-		var network *infrav1.ONEVirtualNetwork
-		if oneCluster.Spec.PrivateNetwork != nil {
-			network = oneCluster.Spec.PrivateNetwork
-		} else {
-			network = oneCluster.Spec.PublicNetwork
-		}
+		network := clusterMachineNetwork(oneCluster)
 
 		// Registers VR backends only for Control-Plane Nodes.
 		var router *infrav1.ONEVirtualRouter
@@ -246,11 +319,41 @@ func (r *ONEMachineReconciler) reconcileNormal(
 		}
 
 		userData := string(dataSecret.Data["value"])
-		if err := externalMachine.FromTemplate(oneMachine.Spec.TemplateName, &userData, network, router); err != nil {
+		if err := externalMachine.FromTemplate(
+			resolveTemplateName(oneCluster, oneMachine.Spec.TemplateName), &userData, network, router,
+		); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
-	setMachineAddress(oneMachine, externalMachine.Address4)
+	if setMachineInstanceState(oneMachine, externalMachine) {
+		return ctrl.Result{}, nil
+	}
+	setMachineAddresses(oneCluster, oneMachine, externalMachine)
+
+	if !externalMachine.Running() {
+		state, lcmState := externalMachine.StateString()
+
+		provisioningTimeout := r.ProvisioningTimeout
+		if oneMachine.Spec.ProvisioningTimeout != nil {
+			provisioningTimeout = oneMachine.Spec.ProvisioningTimeout.Duration
+		}
+		if provisioningTimeout > 0 && time.Since(externalMachine.Started) > provisioningTimeout {
+			failureReason := capierrors.CreateMachineError
+			failureMessage := fmt.Sprintf("VM %d did not reach RUNNING within %s, last state %s %s",
+				externalMachine.ID, provisioningTimeout, state, lcmState)
+			oneMachine.Status.FailureReason = &failureReason
+			oneMachine.Status.FailureMessage = &failureMessage
+			conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisioningTimeoutReason,
+				clusterv1.ConditionSeverityError, "%s", failureMessage)
+			return ctrl.Result{}, nil
+		}
+
+		log.Info("Waiting for VM to be running", "state", state, "lcmState", lcmState)
+		conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceProvisioningReason,
+			clusterv1.ConditionSeverityInfo, "VM is in %s %s state", state, lcmState)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	conditions.MarkTrue(oneMachine, infrav1.InstanceReadyCondition)
 
 	if cluster.Spec.ControlPlaneRef != nil && !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
@@ -261,11 +364,190 @@ func (r *ONEMachineReconciler) reconcileNormal(
 	return ctrl.Result{}, nil
 }
 
-func setMachineAddress(oneMachine *infrav1.ONEMachine, address string) {
-	oneMachine.Status.Addresses = []clusterv1.MachineAddress{
-		{Type: clusterv1.MachineExternalIP, Address: address},
-		{Type: clusterv1.MachineInternalIP, Address: address},
+// setMachineInstanceState mirrors the VM state into the ONEMachine status and
+// reports whether the VM has failed terminally.
+func setMachineInstanceState(oneMachine *infrav1.ONEMachine, externalMachine *cloud.Machine) bool {
+	state, lcmState := externalMachine.StateString()
+	oneMachine.Status.InstanceState = &infrav1.ONEMachineInstanceState{
+		State:    state,
+		LCMState: lcmState,
+	}
+
+	failureMessage := externalMachine.Failure()
+	if failureMessage == "" {
+		return false
+	}
+
+	failureReason := capierrors.UpdateMachineError
+	if !oneMachine.Status.Ready {
+		failureReason = capierrors.CreateMachineError
+	}
+	oneMachine.Status.FailureReason = &failureReason
+	oneMachine.Status.FailureMessage = &failureMessage
+	oneMachine.Status.Ready = false
+	conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceFailedReason,
+		clusterv1.ConditionSeverityError, "%s", failureMessage)
+	return true
+}
+
+// clusterMachineNetwork returns the network machines are attached to by default,
+// with gateway and DNS resolved from the ONECluster status.
+func clusterMachineNetwork(oneCluster *infrav1.ONECluster) *infrav1.ONEVirtualNetwork {
+	var (
+		network       *infrav1.ONEVirtualNetwork
+		networkStatus *infrav1.ONEVirtualNetworkStatus
+	)
+	if oneCluster.Spec.PrivateNetwork != nil {
+		network = oneCluster.Spec.PrivateNetwork.DeepCopy()
+		if oneCluster.Status.Network != nil {
+			networkStatus = oneCluster.Status.Network.Private
+		}
+	} else if oneCluster.Spec.PublicNetwork != nil {
+		network = oneCluster.Spec.PublicNetwork.DeepCopy()
+		if oneCluster.Status.Network != nil {
+			networkStatus = oneCluster.Status.Network.Public
+		}
+	}
+	if network == nil || networkStatus == nil {
+		return network
+	}
+
+	if network.Gateway == nil && networkStatus.Gateway != "" {
+		network.Gateway = &networkStatus.Gateway
+	}
+	if network.Gateway6 == nil && networkStatus.Gateway6 != "" {
+		network.Gateway6 = &networkStatus.Gateway6
 	}
+	if network.DNS == nil && networkStatus.DNS != "" {
+		network.DNS = &networkStatus.DNS
+	}
+	return network
+}
+
+func setMachineAddresses(oneCluster *infrav1.ONECluster, oneMachine *infrav1.ONEMachine, externalMachine *cloud.Machine) {
+	addresses := []clusterv1.MachineAddress{}
+	addAddress := func(addressType clusterv1.MachineAddressType, address string) {
+		for _, existing := range addresses {
+			if existing.Type == addressType && existing.Address == address {
+				return
+			}
+		}
+		addresses = append(addresses, clusterv1.MachineAddress{Type: addressType, Address: address})
+	}
+
+	if nodeName, err := externalMachine.NodeName(); err == nil {
+		addAddress(clusterv1.MachineHostName, nodeName)
+		addAddress(clusterv1.MachineInternalDNS, nodeName)
+	}
+
+	publicNetwork := oneCluster.Spec.PublicNetwork
+	privateNetwork := oneCluster.Spec.PrivateNetwork
+	if publicNetwork == nil {
+		// Without a public network to tell the NICs apart, the primary address is both.
+		if address := externalMachine.Address(); address != "" {
+			addAddress(clusterv1.MachineExternalIP, address)
+			addAddress(clusterv1.MachineInternalIP, address)
+		}
+		for _, nic := range externalMachine.NICs {
+			for _, address := range nic.Addresses {
+				addAddress(clusterv1.MachineInternalIP, address)
+			}
+		}
+	} else {
+		publicAddresses := []string{}
+		hasInternalAddress := false
+		for _, nic := range externalMachine.NICs {
+			for _, address := range nic.Addresses {
+				if nic.NetworkName == publicNetwork.Name {
+					addAddress(clusterv1.MachineExternalIP, address)
+					publicAddresses = append(publicAddresses, address)
+				} else {
+					addAddress(clusterv1.MachineInternalIP, address)
+					hasInternalAddress = true
+				}
+			}
+		}
+		// Without a private network, or on machines attached to the public one only,
+		// the public addresses are also used for in-cluster traffic.
+		if privateNetwork == nil || !hasInternalAddress {
+			for _, address := range publicAddresses {
+				addAddress(clusterv1.MachineInternalIP, address)
+			}
+		}
+	}
+
+	// Fall back to the contextualized addresses if no NIC reported them.
+	for _, address := range []string{externalMachine.Address4, externalMachine.Address6} {
+		if len(address) > 0 && !slices.ContainsFunc(addresses, func(a clusterv1.MachineAddress) bool {
+			return a.Address == address
+		}) {
+			addAddress(clusterv1.MachineInternalIP, address)
+		}
+	}
+
+	oneMachine.Status.Addresses = addresses
+}
+
+// machinePlacementOptions returns the options placing the VM in its failure domain,
+// VR backend, security group and VM group role.
+func machinePlacementOptions(
+	cloudClients *cloud.Clients, oneCluster *infrav1.ONECluster, machine *clusterv1.Machine) ([]cloud.MachineOption, error) {
+
+	machineOpts := []cloud.MachineOption{}
+	if machine.Spec.FailureDomain != nil && *machine.Spec.FailureDomain != "" {
+		failureDomain := findFailureDomain(oneCluster, *machine.Spec.FailureDomain)
+		if failureDomain == nil {
+			return nil, fmt.Errorf("failure domain %s is not defined in ONECluster", *machine.Spec.FailureDomain)
+		}
+		machineOpts = append(machineOpts, cloud.WithMachineFailureDomain(failureDomain))
+	}
+	if oneCluster.Spec.VirtualRouter != nil {
+		externalRouter, err := cloud.NewRouter(cloudClients)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cloud router: %w", err)
+		}
+		err = externalRouter.ByName(fmt.Sprintf("%s-cp", oneCluster.Name))
+		if err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return nil, errors.Wrap(err, "failed to fetch cloud router")
+		}
+		if externalRouter.Exists() {
+			machineOpts = append(machineOpts, cloud.WithMachineRouterID(externalRouter.ID))
+		}
+	}
+	if oneCluster.Status.SecurityGroups != nil {
+		securityGroupID := oneCluster.Status.SecurityGroups.WorkerID
+		if util.IsControlPlaneMachine(machine) {
+			securityGroupID = oneCluster.Status.SecurityGroups.ControlPlaneID
+		}
+		machineOpts = append(machineOpts, cloud.WithMachineSecurityGroups([]int{int(securityGroupID)}))
+	}
+	if oneCluster.Spec.VMGroup != nil {
+		externalVMGroup, err := cloud.NewVMGroup(cloudClients)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize cloud VM group: %w", err)
+		}
+		err = externalVMGroup.ByName(fmt.Sprintf("%s-vmgroup", oneCluster.Name))
+		if err != nil && !errors.Is(err, cloud.ErrNotFound) {
+			return nil, errors.Wrap(err, "failed to fetch cloud VM group")
+		}
+		if externalVMGroup.Exists() {
+			vmGroupRole := cloud.VMGroupRoleWorker
+			if util.IsControlPlaneMachine(machine) {
+				vmGroupRole = cloud.VMGroupRoleControlPlane
+			}
+			machineOpts = append(machineOpts, cloud.WithMachineVMGroup(externalVMGroup.ID, vmGroupRole))
+		}
+	}
+	return machineOpts, nil
+}
+
+func findFailureDomain(oneCluster *infrav1.ONECluster, name string) *infrav1.ONEFailureDomain {
+	for idx := range oneCluster.Spec.FailureDomains {
+		if oneCluster.Spec.FailureDomains[idx].Name == name {
+			return &oneCluster.Spec.FailureDomains[idx]
+		}
+	}
+	return nil
 }
 
 func generateExternalMachineName(machine *clusterv1.Machine, oneMachine *infrav1.ONEMachine) string {
@@ -275,19 +557,103 @@ func generateExternalMachineName(machine *clusterv1.Machine, oneMachine *infrav1
 	return machine.Name
 }
 
+const (
+	// hardTerminateRetryInterval is how long to wait before re-issuing the hard
+	// terminate of a VM which ended up in a failure state.
+	hardTerminateRetryInterval = 30 * time.Second
+	// hardTerminateTimeout is how long a hard terminated VM may take to reach DONE.
+	hardTerminateTimeout = 5 * time.Minute
+)
+
 func (r *ONEMachineReconciler) reconcileDelete(
 	ctx context.Context,
 	oneCluster *infrav1.ONECluster,
 	machine *clusterv1.Machine, oneMachine *infrav1.ONEMachine, externalMachine *cloud.Machine) (ctrl.Result, error) {
 
-	externalMachine.ByName(externalMachine.Name)
+	log := log.FromContext(ctx)
+
+	if err := externalMachine.ByName(externalMachine.Name); err != nil {
+		if !errors.Is(err, cloud.ErrNotFound) {
+			return ctrl.Result{}, errors.Wrap(err, "failed to delete ONEMachine")
+		}
+		// DONE VMs are not listed anymore, so the VM is gone already.
+		log.Info("VM does not exist anymore, removing finalizer")
+		controllerutil.RemoveFinalizer(oneMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+	if externalMachine.Done() {
+		log.Info("VM is DONE, removing finalizer", "vmID", externalMachine.ID)
+		controllerutil.RemoveFinalizer(oneMachine, infrav1.MachineFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	terminationGracePeriod := r.TerminationGracePeriod
+	if oneMachine.Spec.TerminationGracePeriod != nil {
+		terminationGracePeriod = oneMachine.Spec.TerminationGracePeriod.Duration
+	}
+
+	state, lcmState := externalMachine.StateString()
+
+	var stuckMessage string
+	switch conditions.GetReason(oneMachine, infrav1.InstanceReadyCondition) {
+	case infrav1.InstanceTerminatingHardReason:
+		var waited time.Duration
+		if terminatingSince := conditions.GetLastTransitionTime(oneMachine, infrav1.InstanceReadyCondition); terminatingSince != nil {
+			waited = time.Since(terminatingSince.Time)
+		}
+		failure := externalMachine.Failure()
+		switch {
+		case externalMachine.Running():
+			// The VM is still running, the previous hard terminate did not succeed.
+		case failure != "" && waited >= hardTerminateRetryInterval:
+			stuckMessage = failure
+		case waited >= hardTerminateTimeout:
+			stuckState := state
+			if lcmState != "" {
+				stuckState = lcmState
+			}
+			stuckMessage = fmt.Sprintf("VM %d did not reach DONE within %s, it is in %s state",
+				externalMachine.ID, hardTerminateTimeout, stuckState)
+		default:
+			log.Info("Waiting for VM to be DONE", "vmID", externalMachine.ID, "state", state, "lcmState", lcmState)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+	case infrav1.InstanceTerminatingReason:
+		terminatingSince := conditions.GetLastTransitionTime(oneMachine, infrav1.InstanceReadyCondition)
+		if terminatingSince != nil && time.Since(terminatingSince.Time) < terminationGracePeriod {
+			log.Info("Waiting for VM to shut down", "vmID", externalMachine.ID, "state", state, "lcmState", lcmState)
+			return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		log.Info("VM did not shut down within the grace period, falling back to hard terminate", "vmID", externalMachine.ID)
+	default:
+		// Only running VMs can react to ACPI, terminate all the others hard right away.
+		if terminationGracePeriod > 0 && externalMachine.Running() {
+			if err := externalMachine.Terminate(); err == nil {
+				log.Info("Requested graceful termination of VM", "vmID", externalMachine.ID)
+				conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTerminatingReason,
+					clusterv1.ConditionSeverityInfo, "Waiting up to %s for VM to shut down", terminationGracePeriod)
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			} else {
+				log.Error(err, "Failed to terminate VM gracefully, falling back to hard terminate", "vmID", externalMachine.ID)
+			}
+		}
+	}
 
 	if err := externalMachine.Delete(); err != nil {
 		return ctrl.Result{}, errors.Wrap(err, "failed to delete ONEMachine")
 	}
+	// Recreate the condition, so its transition time tracks the last hard terminate.
+	conditions.Delete(oneMachine, infrav1.InstanceReadyCondition)
+	if stuckMessage != "" {
+		log.Info("Re-issued hard terminate of stuck VM", "vmID", externalMachine.ID, "state", state, "lcmState", lcmState)
+		conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTerminatingHardReason,
+			clusterv1.ConditionSeverityWarning, "%s, re-issued hard terminate", stuckMessage)
+	} else {
+		conditions.MarkFalse(oneMachine, infrav1.InstanceReadyCondition, infrav1.InstanceTerminatingHardReason,
+			clusterv1.ConditionSeverityInfo, "Waiting for VM to be DONE")
+	}
 
-	controllerutil.RemoveFinalizer(oneMachine, infrav1.MachineFinalizer)
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 }
 
 func (r *ONEMachineReconciler) SetupWithManager(mgr ctrl.Manager) error {
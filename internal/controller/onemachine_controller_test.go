@@ -0,0 +1,137 @@
+/*
+Copyright 2024, OpenNebula Project, OpenNebula Systems.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"reflect"
+	"testing"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "github.com/OpenNebula/cluster-api-provider-opennebula/api/v1beta1"
+	"github.com/OpenNebula/cluster-api-provider-opennebula/internal/cloud"
+)
+
+func TestSetMachineAddresses(t *testing.T) {
+	public := &infrav1.ONEVirtualNetwork{Name: "public"}
+	private := &infrav1.ONEVirtualNetwork{Name: "private"}
+
+	external := func(address string) clusterv1.MachineAddress {
+		return clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: address}
+	}
+	internal := func(address string) clusterv1.MachineAddress {
+		return clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: address}
+	}
+
+	tests := []struct {
+		name           string
+		publicNetwork  *infrav1.ONEVirtualNetwork
+		privateNetwork *infrav1.ONEVirtualNetwork
+		machine        cloud.Machine
+		want           []clusterv1.MachineAddress
+	}{
+		{
+			name: "no public network",
+			machine: cloud.Machine{
+				Address4: "10.0.0.5",
+				NICs: []cloud.MachineNIC{
+					{NetworkName: "private", Addresses: []string{"10.0.0.5"}},
+					{NetworkName: "storage", Addresses: []string{"172.16.0.5"}},
+				},
+			},
+			want: []clusterv1.MachineAddress{external("10.0.0.5"), internal("10.0.0.5"), internal("172.16.0.5")},
+		},
+		{
+			name:           "public and private networks",
+			publicNetwork:  public,
+			privateNetwork: private,
+			machine: cloud.Machine{
+				Address4: "192.168.1.5",
+				NICs: []cloud.MachineNIC{
+					{NetworkName: "public", Addresses: []string{"192.168.1.5"}},
+					{NetworkName: "private", Addresses: []string{"10.0.0.5"}},
+				},
+			},
+			want: []clusterv1.MachineAddress{external("192.168.1.5"), internal("10.0.0.5")},
+		},
+		{
+			name:          "public network only",
+			publicNetwork: public,
+			machine: cloud.Machine{
+				Address4: "192.168.1.5",
+				NICs: []cloud.MachineNIC{
+					{NetworkName: "public", Addresses: []string{"192.168.1.5"}},
+				},
+			},
+			want: []clusterv1.MachineAddress{external("192.168.1.5"), internal("192.168.1.5")},
+		},
+		{
+			name:           "machine attached to the public network only",
+			publicNetwork:  public,
+			privateNetwork: private,
+			machine: cloud.Machine{
+				Address4: "192.168.1.5",
+				NICs: []cloud.MachineNIC{
+					{NetworkName: "public", Addresses: []string{"192.168.1.5"}},
+				},
+			},
+			want: []clusterv1.MachineAddress{external("192.168.1.5"), internal("192.168.1.5")},
+		},
+		{
+			name:           "dual-stack NICs",
+			publicNetwork:  public,
+			privateNetwork: private,
+			machine: cloud.Machine{
+				Address4: "192.168.1.5",
+				Address6: "2001:db8::5",
+				NICs: []cloud.MachineNIC{
+					{NetworkName: "public", Addresses: []string{"192.168.1.5", "2001:db8::5"}},
+					{NetworkName: "private", Addresses: []string{"10.0.0.5", "fd00::5"}},
+				},
+			},
+			want: []clusterv1.MachineAddress{
+				external("192.168.1.5"), external("2001:db8::5"),
+				internal("10.0.0.5"), internal("fd00::5"),
+			},
+		},
+		{
+			name:          "contextualized address without NICs",
+			publicNetwork: public,
+			machine: cloud.Machine{
+				Address6: "2001:db8::5",
+			},
+			want: []clusterv1.MachineAddress{internal("2001:db8::5")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oneCluster := &infrav1.ONECluster{}
+			oneCluster.Spec.PublicNetwork = tt.publicNetwork
+			oneCluster.Spec.PrivateNetwork = tt.privateNetwork
+			oneMachine := &infrav1.ONEMachine{}
+
+			// Machines which do not exist report no host names.
+			machine := tt.machine
+			machine.ID = -1
+
+			setMachineAddresses(oneCluster, oneMachine, &machine)
+			if !reflect.DeepEqual(oneMachine.Status.Addresses, tt.want) {
+				t.Fatalf("setMachineAddresses() = %v, want %v", oneMachine.Status.Addresses, tt.want)
+			}
+		})
+	}
+}